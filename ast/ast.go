@@ -1,6 +1,11 @@
 package ast
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/chenjunwen186/sqlexpr/token"
@@ -14,6 +19,9 @@ type Expression interface {
 type Identifier struct {
 	Token token.Token
 	Value string
+
+	// Set for quoted identifiers lexed with the lexer option `BackslashEscapesInIdentifiers`
+	BackslashEscapes bool
 }
 
 func (i *Identifier) TokenLiteral() string {
@@ -24,6 +32,86 @@ func (i *Identifier) String() string {
 	return i.Value
 }
 
+// Name returns the decoded name of the identifier, without the quotes and
+// with the doubled delimiters resolved, so "a""b" is named `a"b`.
+// A backslash escapes the next character only if `BackslashEscapes` is set.
+func (i *Identifier) Name() string {
+	var quote rune
+	switch i.Token.Type {
+	case token.BACK_QUOTE_IDENT:
+		quote = '`'
+	case token.DOUBLE_QUOTE_IDENT:
+		quote = '"'
+	default:
+		return i.Value
+	}
+
+	runes := []rune(i.Value)
+	if len(runes) < 2 {
+		return i.Value
+	}
+	runes = runes[1 : len(runes)-1]
+
+	var b strings.Builder
+	for j := 0; j < len(runes); j++ {
+		switch {
+		case i.BackslashEscapes && runes[j] == '\\' && j+1 < len(runes):
+			j++
+		case runes[j] == quote && j+1 < len(runes) && runes[j+1] == quote:
+			j++
+		}
+		b.WriteRune(runes[j])
+	}
+
+	return b.String()
+}
+
+// QualifiedIdentifier is a dotted name like `orders.amount` or `db.schema.t.col`
+type QualifiedIdentifier struct {
+	Token token.Token // The first `.` token
+	Parts []*Identifier
+}
+
+func (q *QualifiedIdentifier) TokenLiteral() string {
+	return q.Token.Literal
+}
+
+func (q *QualifiedIdentifier) String() string {
+	parts := make([]string, len(q.Parts))
+	for i, part := range q.Parts {
+		parts[i] = part.String()
+	}
+
+	return strings.Join(parts, token.PERIOD)
+}
+
+// Wildcard is a bare `*`, like the argument of `COUNT(*)`
+type Wildcard struct {
+	Token token.Token
+}
+
+func (w *Wildcard) TokenLiteral() string {
+	return w.Token.Literal
+}
+
+func (w *Wildcard) String() string {
+	return token.ASTERISK
+}
+
+// QualifiedWildcard is all columns of a table, like `users.*`
+type QualifiedWildcard struct {
+	Token token.Token // The `.` token
+	Table *Identifier
+}
+
+func (q *QualifiedWildcard) TokenLiteral() string {
+	return q.Token.Literal
+}
+
+func (q *QualifiedWildcard) String() string {
+	return q.Table.String() + token.PERIOD + token.ASTERISK
+}
+
 type PrefixExpression struct {
 	Token token.Token
 	Right Expression
@@ -33,6 +121,12 @@ func (p *PrefixExpression) Operator() string {
 	return p.Token.Literal
 }
 
+// Symbol returns the canonical spelling of the operator,
+// keywords are always upper-cased regardless of the input casing
+func (p *PrefixExpression) Symbol() string {
+	return string(p.Token.Type)
+}
+
 func (p *PrefixExpression) TokenLiteral() string {
 	return p.Token.Literal
 }
@@ -40,11 +134,11 @@ func (p *PrefixExpression) TokenLiteral() string {
 func (p *PrefixExpression) String() string {
 	var space string
 	switch p.Token.Type {
-	case token.DISTINCT:
+	case token.DISTINCT, token.NOT:
 		space = " "
 	}
 
-	return "(" + p.Operator() + space + p.Right.String() + ")"
+	return "(" + p.Symbol() + space + p.Right.String() + ")"
 }
 
 type InfixExpression struct {
@@ -57,12 +151,29 @@ func (i *InfixExpression) Operator() token.Type {
 	return i.Token.Type
 }
 
+// Symbol returns the canonical spelling of the operator,
+// keywords are always upper-cased regardless of the input casing
+func (i *InfixExpression) Symbol() string {
+	return string(i.Operator())
+}
+
+// IsInUnnest reports whether the expression is `x [NOT] IN UNNEST(array)`,
+// which tests membership in the elements of the array
+func (i *InfixExpression) IsInUnnest() bool {
+	if i.Operator() != token.IN && i.Operator() != token.NOT_IN {
+		return false
+	}
+
+	_, ok := i.Right.(*UnnestExpression)
+	return ok
+}
+
 func (i *InfixExpression) TokenLiteral() string {
 	return i.Token.Literal
 }
 
 func (i *InfixExpression) String() string {
-	return "(" + i.Left.String() + " " + string(i.Operator()) + " " + i.Right.String() + ")"
+	return "(" + i.Left.String() + " " + i.Symbol() + " " + i.Right.String() + ")"
 }
 
 type NullLiteral struct {
@@ -76,6 +187,18 @@ func (n *NullLiteral) String() string {
 	return n.Token.Literal
 }
 
+// DefaultLiteral is the keyword `DEFAULT` used as a value, like `col = DEFAULT`
+type DefaultLiteral struct {
+	token.Token
+}
+
+func (d *DefaultLiteral) TokenLiteral() string {
+	return d.Token.Literal
+}
+func (d *DefaultLiteral) String() string {
+	return d.Token.Literal
+}
+
 type BooleanLiteral struct {
 	token.Token
 }
@@ -93,9 +216,12 @@ func (b *BooleanLiteral) Value() bool {
 }
 
 type CallExpression struct {
-	Token     token.Token
-	Fn        Expression
-	Arguments []Expression
+	Token       token.Token
+	Fn          Expression
+	Arguments   []Expression
+	OrderBy     []*OrderByItem // Optional, aggregate `ORDER BY` inside the parentheses, like `ARRAY_AGG(x ORDER BY y)`
+	WithinGroup []*OrderByItem // Optional, `WITHIN GROUP (ORDER BY ...)` of ordered-set aggregates
+	Distinct    bool           // The aggregate modifier of `COUNT(DISTINCT x)`
 }
 
 func (c *CallExpression) TokenLiteral() string {
@@ -108,7 +234,30 @@ func (c *CallExpression) String() string {
 		args[i] = arg.String()
 	}
 
-	return c.Fn.String() + "(" + strings.Join(args, ", ") + ")"
+	var orderBy string
+	if c.OrderBy != nil {
+		items := make([]string, len(c.OrderBy))
+		for i, item := range c.OrderBy {
+			items[i] = item.String()
+		}
+		orderBy = " ORDER BY " + strings.Join(items, ", ")
+	}
+
+	var withinGroup string
+	if c.WithinGroup != nil {
+		items := make([]string, len(c.WithinGroup))
+		for i, item := range c.WithinGroup {
+			items[i] = item.String()
+		}
+		withinGroup = " WITHIN GROUP (ORDER BY " + strings.Join(items, ", ") + ")"
+	}
+
+	var distinct string
+	if c.Distinct {
+		distinct = token.DISTINCT + " "
+	}
+
+	return c.Fn.String() + "(" + distinct + strings.Join(args, ", ") + orderBy + ")" + withinGroup
 }
 
 type StringLiteral struct {
@@ -124,6 +273,28 @@ func (t *StringLiteral) String() string {
 	return t.Token.Literal
 }
 
+// Decode returns the text of a quoted string without the quotes,
+// with doubled quotes and backslash-escaped quotes resolved to a single quote.
+// Other backslash sequences are kept as written,
+// hex and bit strings like `x'4F'` are returned as written.
+func (t *StringLiteral) Decode() string {
+	runes := []rune(t.Value)
+	if t.Token.Type != token.STRING || len(runes) < 2 {
+		return t.Value
+	}
+	runes = runes[1 : len(runes)-1]
+
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if (runes[i] == '\\' || runes[i] == '\'') && i+1 < len(runes) && runes[i+1] == '\'' {
+			i++
+		}
+		b.WriteRune(runes[i])
+	}
+
+	return b.String()
+}
+
 type NumberLiteral struct {
 	token.Token
 }
@@ -136,10 +307,83 @@ func (t *NumberLiteral) String() string {
 	return t.Literal
 }
 
+// IsSpecialFloat reports whether the literal is `Infinity` or `NaN`,
+// which are only parsed as numbers with the parser option `SpecialFloatLiterals`
+func (t *NumberLiteral) IsSpecialFloat() bool {
+	switch strings.ToUpper(t.Literal) {
+	case "INFINITY", "NAN":
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrNumberRange is wrapped by the error of `NumberLiteral.Value`
+// for literals beyond the float64 range, like `1e400`
+var ErrNumberRange = errors.New("out of the float64 range")
+
+// IntValue returns the value of an integer literal, decimal like `10`, octal `0755`,
+// hexadecimal `0xFF` or binary `0b101`. It is false for other literals
+// and integers beyond the int64 range, use `Value` for them.
+func (t *NumberLiteral) IntValue() (int64, bool) {
+	digits, base := numberBase(t.Literal)
+	i, err := strconv.ParseInt(digits, base, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// Value returns the numeric value of the literal, decimal like `1.23e-2`,
+// octal `0755`, hexadecimal `0xFF` and binary `0b101` literals are supported.
+// All consumers of number literals go through it or `IntValue`, so they agree on the value.
+func (t *NumberLiteral) Value() (float64, error) {
+	if t.IsSpecialFloat() {
+		return strconv.ParseFloat(t.Literal, 64)
+	}
+	if i, ok := t.IntValue(); ok {
+		return float64(i), nil
+	}
+
+	// big.Float doesn't overflow on exponents like `1e400`
+	digits, base := numberBase(t.Literal)
+	f, _, err := big.ParseFloat(digits, base, 53, big.ToNearestEven)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number literal %s: %w", t.Literal, err)
+	}
+
+	v, _ := f.Float64()
+	if math.IsInf(v, 0) {
+		return 0, fmt.Errorf("number literal %s is %w", t.Literal, ErrNumberRange)
+	}
+
+	return v, nil
+}
+
+// Splits off the `0x`, `0b` or `0` prefix of a literal, a leading `0` is only octal
+// for integers like the lexer reads them, so `0.5` and `0e3` are decimal
+func numberBase(literal string) (string, int) {
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			return literal[2:], 16
+		case 'b', 'B':
+			return literal[2:], 2
+		}
+	}
+	if len(literal) > 1 && literal[0] == '0' && strings.Trim(literal, "01234567") == "" {
+		return literal[1:], 8
+	}
+
+	return literal, 10
+}
+
 type CaseWhenExpression struct {
-	Token token.Token
-	Whens []When
-	Else  Expression
+	Token   token.Token
+	Operand Expression // Optional, `x` of the simple form `CASE x WHEN 1 THEN ...`
+	Whens   []When
+	Else    Expression
 }
 
 func (c *CaseWhenExpression) TokenLiteral() string {
@@ -157,12 +401,21 @@ func (c *CaseWhenExpression) String() string {
 		elseStr = " ELSE " + c.Else.String()
 	}
 
-	return "CASE " + strings.Join(whens, " ") + elseStr + " END"
+	var operand string
+	if c.Operand != nil {
+		operand = c.Operand.String() + " "
+	}
+
+	return "CASE " + operand + strings.Join(whens, " ") + elseStr + " END"
 }
 
 type When struct {
 	Cond Expression
 	Then Expression
+
+	// The comments right before `WHEN` joined by newlines, like `/* first */`,
+	// only parsed with the lexer option `AllowComments`
+	Comment string
 }
 
 func (c *When) String() string {
@@ -210,3 +463,599 @@ func (t *TupleExpression) String() string {
 	}
 	return token.LPAREN + strings.Join(exprs, ", ") + token.RPAREN
 }
+
+type ArrayLiteral struct {
+	Token    token.Token // The `[` token, or the `ARRAY` token of `ARRAY[1, 2]`
+	Elements []Expression
+
+	// Set for the Postgres array constructor `ARRAY[...]`
+	Typed bool
+}
+
+func (a *ArrayLiteral) TokenLiteral() string {
+	return a.Token.Literal
+}
+
+func (a *ArrayLiteral) String() string {
+	elements := make([]string, len(a.Elements))
+	for i, element := range a.Elements {
+		elements[i] = element.String()
+	}
+	literal := token.LBRACKET + strings.Join(elements, ", ") + token.RBRACKET
+	if a.Typed {
+		return "ARRAY" + literal
+	}
+	return literal
+}
+
+// An interval like `INTERVAL 3 MONTH`
+type IntervalExpression struct {
+	Token token.Token // The `INTERVAL` token
+	Value Expression
+	Unit  token.Type // A time unit like DAY or MONTH
+}
+
+func (i *IntervalExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IntervalExpression) String() string {
+	return token.INTERVAL + " " + i.Value.String() + " " + string(i.Unit)
+}
+
+// A ClickHouse lambda like `x -> x > 1` or `(x, y) -> x + y`
+type LambdaExpression struct {
+	Token  token.Token // The `->` token
+	Params []*Identifier
+	Body   Expression
+}
+
+func (l *LambdaExpression) TokenLiteral() string {
+	return l.Token.Literal
+}
+
+func (l *LambdaExpression) String() string {
+	if len(l.Params) == 1 {
+		return l.Params[0].String() + " " + token.PRT + " " + l.Body.String()
+	}
+
+	params := make([]string, len(l.Params))
+	for i, param := range l.Params {
+		params[i] = param.String()
+	}
+	return "(" + strings.Join(params, ", ") + ") " + token.PRT + " " + l.Body.String()
+}
+
+// A conditional like `cond ? a : b`
+type TernaryExpression struct {
+	Token token.Token // The `?` token
+	Cond  Expression
+	True  Expression
+	False Expression
+}
+
+func (t *TernaryExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *TernaryExpression) String() string {
+	return "(" + t.Cond.String() + " " + token.QUESTION + " " + t.True.String() + " " + token.COLON + " " + t.False.String() + ")"
+}
+
+// An alias of a projection like `COUNT(*) AS c`, or the implicit `COUNT(*) c`
+type AliasExpression struct {
+	Token token.Token // The `AS` token, or the alias of an implicit alias
+	Expr  Expression
+	Alias *Identifier
+}
+
+func (a *AliasExpression) TokenLiteral() string {
+	return a.Token.Literal
+}
+
+// The alias is outermost, so it is not parenthesized
+func (a *AliasExpression) String() string {
+	return a.Expr.String() + " " + token.AS + " " + a.Alias.String()
+}
+
+// A JSON access like `data -> 'a'` or `data ->> 0`
+type JSONAccessExpression struct {
+	Token token.Token // The `->` or `->>` token
+	Left  Expression
+	Index Expression
+}
+
+// Operator returns `token.PRT` for `->` or `token.PRT2` for `->>`
+func (j *JSONAccessExpression) Operator() token.Type {
+	return j.Token.Type
+}
+
+func (j *JSONAccessExpression) TokenLiteral() string {
+	return j.Token.Literal
+}
+
+func (j *JSONAccessExpression) String() string {
+	return "(" + j.Left.String() + " " + string(j.Operator()) + " " + j.Index.String() + ")"
+}
+
+// A subscript like `a[1]` or `m['k']`
+type IndexExpression struct {
+	Token token.Token // The `[` token
+	Left  Expression
+	Index Expression
+}
+
+func (i *IndexExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IndexExpression) String() string {
+	return i.Left.String() + token.LBRACKET + i.Index.String() + token.RBRACKET
+}
+
+type CollateExpression struct {
+	Token     token.Token
+	Left      Expression
+	Collation *Identifier
+}
+
+func (c *CollateExpression) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+func (c *CollateExpression) String() string {
+	return "(" + c.Left.String() + " " + token.COLLATE + " " + c.Collation.String() + ")"
+}
+
+type PositionExpression struct {
+	Token  token.Token // The `(` token
+	Substr Expression
+	Str    Expression
+}
+
+func (p *PositionExpression) TokenLiteral() string {
+	return p.Token.Literal
+}
+
+func (p *PositionExpression) String() string {
+	return "POSITION(" + p.Substr.String() + " " + token.IN + " " + p.Str.String() + ")"
+}
+
+// `UNNEST(array)`, the value list of BigQuery `x IN UNNEST(array)`
+type UnnestExpression struct {
+	Token token.Token // The `(` token
+	Array Expression
+}
+
+func (u *UnnestExpression) TokenLiteral() string {
+	return u.Token.Literal
+}
+
+func (u *UnnestExpression) String() string {
+	return "UNNEST(" + u.Array.String() + ")"
+}
+
+type TrimExpression struct {
+	Token  token.Token // The `(` token
+	Spec   string      // LEADING, TRAILING, BOTH or empty
+	Chars  Expression  // Optional
+	Source Expression
+}
+
+func (t *TrimExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *TrimExpression) String() string {
+	var b strings.Builder
+	b.WriteString("TRIM(")
+	if t.Spec != "" {
+		b.WriteString(t.Spec + " ")
+	}
+	if t.Chars != nil {
+		b.WriteString(t.Chars.String() + " ")
+	}
+	b.WriteString(token.FROM + " " + t.Source.String() + ")")
+
+	return b.String()
+}
+
+type SubstringExpression struct {
+	Token  token.Token // The `(` token
+	Source Expression
+	From   Expression // Optional if For is set
+	For    Expression // Optional
+}
+
+func (s *SubstringExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s *SubstringExpression) String() string {
+	var b strings.Builder
+	b.WriteString("SUBSTRING(" + s.Source.String())
+	if s.From != nil {
+		b.WriteString(" " + token.FROM + " " + s.From.String())
+	}
+	if s.For != nil {
+		b.WriteString(" " + token.FOR + " " + s.For.String())
+	}
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// `EXTRACT(field FROM source)`
+type ExtractExpression struct {
+	Token  token.Token // The `(` token
+	Field  token.Type  // A time unit like YEAR or DAY
+	Source Expression
+}
+
+func (e *ExtractExpression) TokenLiteral() string {
+	return e.Token.Literal
+}
+
+func (e *ExtractExpression) String() string {
+	return "EXTRACT(" + string(e.Field) + " " + token.FROM + " " + e.Source.String() + ")"
+}
+
+// The regular expression form `SUBSTRING(source SIMILAR pattern ESCAPE escape)`
+type SubstringSimilarExpression struct {
+	Token   token.Token // The `(` token
+	Source  Expression
+	Pattern Expression
+	Escape  Expression
+}
+
+func (s *SubstringSimilarExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s *SubstringSimilarExpression) String() string {
+	return "SUBSTRING(" + s.Source.String() +
+		" " + token.SIMILAR + " " + s.Pattern.String() +
+		" " + token.ESCAPE + " " + s.Escape.String() + ")"
+}
+
+type OverlayExpression struct {
+	Token   token.Token // The `(` token
+	Source  Expression
+	Placing Expression
+	From    Expression
+	For     Expression // Optional
+}
+
+func (o *OverlayExpression) TokenLiteral() string {
+	return o.Token.Literal
+}
+
+func (o *OverlayExpression) String() string {
+	var b strings.Builder
+	b.WriteString("OVERLAY(" + o.Source.String())
+	b.WriteString(" " + token.PLACING + " " + o.Placing.String())
+	b.WriteString(" " + token.FROM + " " + o.From.String())
+	if o.For != nil {
+		b.WriteString(" " + token.FOR + " " + o.For.String())
+	}
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// A pattern with its escape character, the right side of `a LIKE 'x!%' ESCAPE '!'`
+type EscapeExpression struct {
+	Token   token.Token // The `ESCAPE` token
+	Pattern Expression
+	Escape  Expression
+}
+
+func (e *EscapeExpression) TokenLiteral() string {
+	return e.Token.Literal
+}
+
+func (e *EscapeExpression) String() string {
+	return e.Pattern.String() + " " + token.ESCAPE + " " + e.Escape.String()
+}
+
+// `LIKE ANY (...)`, `LIKE ALL (...)` or `LIKE SOME (...)` and their `NOT LIKE` forms
+type LikeExpression struct {
+	Token      token.Token // The `LIKE` or `NOT LIKE` token
+	Left       Expression
+	Quantifier token.Type // ANY, ALL or SOME
+	Patterns   []Expression
+}
+
+func (l *LikeExpression) Operator() token.Type {
+	return l.Token.Type
+}
+
+func (l *LikeExpression) TokenLiteral() string {
+	return l.Token.Literal
+}
+
+func (l *LikeExpression) String() string {
+	patterns := make([]string, len(l.Patterns))
+	for i, pattern := range l.Patterns {
+		patterns[i] = pattern.String()
+	}
+
+	return "(" + l.Left.String() + " " + string(l.Operator()) + " " + string(l.Quantifier) + " (" + strings.Join(patterns, ", ") + "))"
+}
+
+// `x IS [NOT] NULL`, `x IS [NOT] TRUE` or `x IS [NOT] FALSE`,
+// `IS` followed by anything else is an `InfixExpression`
+type IsExpression struct {
+	Token     token.Token // The `IS` or `IS NOT` token
+	Left      Expression
+	Negated   bool
+	Predicate token.Type // NULL, TRUE or FALSE
+
+	// The predicate as written, like `null`, the canonical Predicate is used when empty
+	PredicateLiteral string
+}
+
+func (i *IsExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IsExpression) String() string {
+	op := token.IS
+	if i.Negated {
+		op = token.IS_NOT
+	}
+
+	predicate := i.PredicateLiteral
+	if predicate == "" {
+		predicate = string(i.Predicate)
+	}
+
+	return "(" + i.Left.String() + " " + op + " " + predicate + ")"
+}
+
+type IsNormalizedExpression struct {
+	Token   token.Token // The `IS` or `IS NOT` token
+	Expr    Expression
+	Form    string // NFC, NFD, NFKC, NFKD or empty
+	Negated bool
+}
+
+func (i *IsNormalizedExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IsNormalizedExpression) String() string {
+	op := token.IS
+	if i.Negated {
+		op = token.IS_NOT
+	}
+
+	var form string
+	if i.Form != "" {
+		form = " " + i.Form
+	}
+
+	return "(" + i.Expr.String() + " " + op + " " + token.NORMALIZED + form + ")"
+}
+
+// The XML predicate `x IS [NOT] DOCUMENT`
+type IsDocumentExpression struct {
+	Token   token.Token // The `IS` or `IS NOT` token
+	Expr    Expression
+	Negated bool
+}
+
+func (i *IsDocumentExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IsDocumentExpression) String() string {
+	op := token.IS
+	if i.Negated {
+		op = token.IS_NOT
+	}
+
+	return "(" + i.Expr.String() + " " + op + " " + token.DOCUMENT + ")"
+}
+
+// The Oracle nested table predicate `x IS [NOT] A SET`
+type IsASetExpression struct {
+	Token   token.Token // The `IS` or `IS NOT` token
+	Expr    Expression
+	Negated bool
+}
+
+func (i *IsASetExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IsASetExpression) String() string {
+	op := token.IS
+	if i.Negated {
+		op = token.IS_NOT
+	}
+
+	return "(" + i.Expr.String() + " " + op + " " + token.A + " " + token.SET + ")"
+}
+
+type IsOfExpression struct {
+	Token   token.Token // The `IS` or `IS NOT` token
+	Expr    Expression
+	Types   []*TypeReference
+	Negated bool
+}
+
+func (i *IsOfExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *IsOfExpression) String() string {
+	op := token.IS
+	if i.Negated {
+		op = token.IS_NOT
+	}
+
+	types := make([]string, len(i.Types))
+	for j, typ := range i.Types {
+		types[j] = typ.String()
+	}
+
+	return "(" + i.Expr.String() + " " + op + " " + token.OF + " (" + strings.Join(types, ", ") + "))"
+}
+
+// A type name like `INT` or `DECIMAL(10, 2)`
+type TypeReference struct {
+	Token token.Token
+	Name  string
+	Args  []Expression
+
+	// The number of `[]` suffixes of an array type, like 1 for `int[]`
+	ArrayDims int
+}
+
+func (t *TypeReference) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *TypeReference) String() string {
+	dims := strings.Repeat(token.LBRACKET+token.RBRACKET, t.ArrayDims)
+	if t.Args == nil {
+		return t.Name + dims
+	}
+
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = arg.String()
+	}
+
+	return t.Name + "(" + strings.Join(args, ", ") + ")" + dims
+}
+
+// `CAST(x AS type)` or the Postgres `x::type`
+type CastExpression struct {
+	Token  token.Token // The `(` token, or the `::` token of `x::type`
+	Expr   Expression
+	Type   *TypeReference
+	Format Expression // Optional
+}
+
+func (c *CastExpression) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+// IsDoubleColon reports whether the cast is written as `x::type`
+func (c *CastExpression) IsDoubleColon() bool {
+	return c.Token.Type == token.COLON2
+}
+
+func (c *CastExpression) String() string {
+	if c.IsDoubleColon() {
+		return "(" + c.Expr.String() + token.COLON2 + c.Type.String() + ")"
+	}
+
+	var format string
+	if c.Format != nil {
+		format = " FORMAT " + c.Format.String()
+	}
+
+	return "CAST(" + c.Expr.String() + " " + token.AS + " " + c.Type.String() + format + ")"
+}
+
+// Postgres `a OPERATOR(schema.op) b`
+type CustomOperatorExpression struct {
+	Token    token.Token // The `OPERATOR` token
+	Left     Expression
+	Operator string // The qualified operator name like `pg_catalog.+`
+	Right    Expression
+}
+
+func (c *CustomOperatorExpression) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+func (c *CustomOperatorExpression) String() string {
+	return "(" + c.Left.String() + " " + token.OPERATOR + "(" + c.Operator + ") " + c.Right.String() + ")"
+}
+
+// A named argument of a call like `x => 1` in `f(x => 1)`
+type NamedArgument struct {
+	Token token.Token // The `=>` token
+	Name  *Identifier
+	Value Expression
+}
+
+func (n *NamedArgument) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+func (n *NamedArgument) String() string {
+	return n.Name.String() + " " + token.EQ_GT + " " + n.Value.String()
+}
+
+// Kinds of `GroupingConstruct`
+const (
+	GroupingRollup = "ROLLUP"
+	GroupingCube   = "CUBE"
+	GroupingSets   = "GROUPING SETS"
+)
+
+// `ROLLUP(a, b)`, `CUBE(a, b)` or `GROUPING SETS((a, b), c, ())`,
+// only parsed with the parser option `GroupingConstructs`
+type GroupingConstruct struct {
+	Token token.Token // The `ROLLUP`, `CUBE` or `GROUPING` token
+	Kind  string      // GroupingRollup, GroupingCube or GroupingSets
+	Args  []Expression
+}
+
+func (g *GroupingConstruct) TokenLiteral() string {
+	return g.Token.Literal
+}
+
+func (g *GroupingConstruct) String() string {
+	args := make([]string, len(g.Args))
+	for i, arg := range g.Args {
+		args[i] = arg.String()
+	}
+
+	return g.Kind + "(" + strings.Join(args, ", ") + ")"
+}
+
+// An item of `ORDER BY`, like `x DESC`
+type OrderByItem struct {
+	Token     token.Token // The first token of the item
+	Expr      Expression
+	Direction token.Type // Optional, `ASC` or `DESC`
+}
+
+func (o *OrderByItem) TokenLiteral() string {
+	return o.Token.Literal
+}
+
+func (o *OrderByItem) String() string {
+	if o.Direction == "" {
+		return o.Expr.String()
+	}
+
+	return o.Expr.String() + " " + string(o.Direction)
+}
+
+// Ordinal returns the 1-based position of a projection referenced by the item,
+// like `1` in `ORDER BY 1, 2 DESC`, which is a positive decimal integer literal
+func (o *OrderByItem) Ordinal() (int, bool) {
+	v, ok := o.Expr.(*NumberLiteral)
+	if !ok {
+		return 0, false
+	}
+	for _, c := range v.Literal {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+
+	n, err := strconv.Atoi(v.Literal)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+
+	return n, true
+}
@@ -0,0 +1,45 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestIdentifierName(t *testing.T) {
+	type TestCase struct {
+		tokenType        token.Type
+		value            string
+		backslashEscapes bool
+		expected         string
+	}
+
+	inputs := []TestCase{
+		{token.IDENT, "abc", false, "abc"},
+		{token.IDENT, "abc", true, "abc"},
+		{token.BACK_QUOTE_IDENT, "`abc`", false, "abc"},
+		{token.BACK_QUOTE_IDENT, "`a``b`", false, "a`b"},
+		{token.BACK_QUOTE_IDENT, "````", false, "`"},
+		{token.BACK_QUOTE_IDENT, "`a\\b`", false, "a\\b"},
+		{token.BACK_QUOTE_IDENT, "`a\\``", false, "a\\`"},
+		{token.BACK_QUOTE_IDENT, "`a\\`b`", true, "a`b"},
+		{token.BACK_QUOTE_IDENT, "`a\\\\b`", true, "a\\b"},
+		{token.BACK_QUOTE_IDENT, "`a``b`", true, "a`b"},
+		{token.DOUBLE_QUOTE_IDENT, `"abc"`, false, "abc"},
+		{token.DOUBLE_QUOTE_IDENT, `"a""b"`, false, `a"b`},
+		{token.DOUBLE_QUOTE_IDENT, `"a\b"`, false, `a\b`},
+		{token.DOUBLE_QUOTE_IDENT, `"a\"b"`, true, `a"b`},
+		{token.DOUBLE_QUOTE_IDENT, `"a""b"`, true, `a"b`},
+	}
+	for _, input := range inputs {
+		ident := &ast.Identifier{
+			Token:            token.Token{Type: input.tokenType, Literal: input.value},
+			Value:            input.value,
+			BackslashEscapes: input.backslashEscapes,
+		}
+		if actual := ident.Name(); actual != input.expected {
+			t.Errorf("Name() of %s (backslash escapes %t) not %q, got %q", input.value, input.backslashEscapes, input.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+// CostOptions are the weights used by `EstimateCostWithOptions`
+type CostOptions struct {
+	// Weights of function calls by upper-cased name, like `REGEXP_LIKE`
+	Functions map[string]int
+
+	// The weight of a call to a function not in Functions
+	Call int
+
+	// Weights of operators by token type, like `token.LIKE`, other operators weigh 1
+	Operators map[token.Type]int
+}
+
+// DefaultCostOptions returns the default weights of `EstimateCost`,
+// pattern matching like LIKE and the regular expression functions are heavier.
+// The maps are new on each call, so they can be modified to override weights.
+func DefaultCostOptions() CostOptions {
+	return CostOptions{
+		Functions: map[string]int{
+			"REGEXP_LIKE":     20,
+			"REGEXP_MATCH":    20,
+			"REGEXP_MATCHES":  20,
+			"REGEXP_CONTAINS": 20,
+			"REGEXP_EXTRACT":  20,
+			"REGEXP_SUBSTR":   20,
+			"REGEXP_REPLACE":  20,
+			"RLIKE":           20,
+		},
+		Call: 2,
+		Operators: map[token.Type]int{
+			token.LIKE:     10,
+			token.NOT_LIKE: 10,
+		},
+	}
+}
+
+// EstimateCost returns a heuristic cost of evaluating the expression with `DefaultCostOptions`,
+// so a service can reject expensive filters. Literals and identifiers cost 1,
+// operators cost their weight plus their operands and calls their function weight plus their arguments.
+func EstimateCost(expr Expression) int {
+	return EstimateCostWithOptions(expr, DefaultCostOptions())
+}
+
+// EstimateCostWithOptions is `EstimateCost` with the weights of opts
+func EstimateCostWithOptions(expr Expression, opts CostOptions) int {
+	cost := 0
+	WalkContext(expr, func(node, _ Expression, field string, _ int) bool {
+		// The function name is part of the call
+		if field == "Fn" {
+			return false
+		}
+
+		switch v := node.(type) {
+		case *QualifiedIdentifier, *QualifiedWildcard:
+			cost++
+			return false
+		case *CallExpression:
+			cost += opts.callCost(v)
+		case *InfixExpression:
+			cost += opts.operatorCost(v.Operator())
+		case *PrefixExpression:
+			cost += opts.operatorCost(v.Token.Type)
+		case *LikeExpression:
+			cost += opts.operatorCost(v.Operator())
+		default:
+			cost++
+		}
+		return true
+	})
+
+	return cost
+}
+
+func (o CostOptions) callCost(call *CallExpression) int {
+	if weight, ok := o.Functions[strings.ToUpper(call.Fn.String())]; ok {
+		return weight
+	}
+
+	return o.Call
+}
+
+func (o CostOptions) operatorCost(op token.Type) int {
+	if weight, ok := o.Operators[op]; ok {
+		return weight
+	}
+
+	return 1
+}
@@ -0,0 +1,67 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestEstimateCost(t *testing.T) {
+	type TestCase struct {
+		input string
+		cost  int
+	}
+
+	inputs := []TestCase{
+		{"a", 1},
+		{"1", 1},
+		{"t.col", 1},
+		{"a = 1", 3},
+		{"-a", 2},
+		{"a = 1 AND b = 2", 7},
+		{"lower(a)", 3},
+		{"COUNT(*)", 3},
+		{"b LIKE 'x%'", 12},
+		{"b NOT LIKE 'x%'", 12},
+		{"REGEXP_LIKE(a, '^[a-z]+$')", 22},
+		{"regexp_like(a, '^[a-z]+$')", 22},
+		{"REGEXP_LIKE(a, '^[a-z]+$') AND b LIKE '%x%'", 35},
+	}
+	for _, input := range inputs {
+		if actual := ast.EstimateCost(parseExpression(t, input.input)); actual != input.cost {
+			t.Errorf("EstimateCost(%q) not %d, got %d", input.input, input.cost, actual)
+		}
+	}
+
+	cheap := ast.EstimateCost(parseExpression(t, "a = 1"))
+	expensive := ast.EstimateCost(parseExpression(t, "REGEXP_LIKE(a, '...') AND b LIKE '...'"))
+	if cheap >= expensive {
+		t.Errorf("a = 1 should be cheaper than the pattern matching, got %d and %d", cheap, expensive)
+	}
+}
+
+func TestEstimateCostWithOptions(t *testing.T) {
+	opts := ast.DefaultCostOptions()
+	opts.Functions["LOWER"] = 5
+	opts.Operators[token.LIKE] = 100
+	opts.Call = 3
+
+	inputs := map[string]int{
+		"lower(a)":      6,
+		"upper(a)":      4,
+		"b LIKE 'x'":    102,
+		"a = 1":         3,
+		"REGEXP_LIKE()": 20,
+	}
+	for input, cost := range inputs {
+		if actual := ast.EstimateCostWithOptions(parseExpression(t, input), opts); actual != cost {
+			t.Errorf("EstimateCostWithOptions(%q) not %d, got %d", input, cost, actual)
+		}
+	}
+
+	// The defaults are not changed by modifying the returned options
+	if actual := ast.EstimateCost(parseExpression(t, "b LIKE 'x'")); actual != 12 {
+		t.Errorf("EstimateCost() not %d, got %d", 12, actual)
+	}
+}
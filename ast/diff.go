@@ -0,0 +1,123 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diff describes the first structural difference between the expected tree a
+// and the actual tree b in pre-order, or returns an empty string if they are equal.
+//
+// The difference is reported with the path from the root, made of the field names
+// used by `WalkContext`, like "at Arguments[1].Left: expected ..., got ...".
+// Operators are compared by token type, so `and` and `AND` are equal.
+func Diff(a, b Expression) string {
+	return diff(a, b, "")
+}
+
+func diff(a, b Expression, path string) string {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return ""
+		}
+		return mismatch(path, describe(a), describe(b))
+	}
+
+	if fmt.Sprintf("%T", a) != fmt.Sprintf("%T", b) {
+		return mismatch(path, describe(a), describe(b))
+	}
+
+	if opA, opB := operatorOf(a), operatorOf(b); opA != opB {
+		return mismatch(path, "operator "+opA, "operator "+opB)
+	}
+
+	childrenA, childrenB := children(a), children(b)
+	if len(childrenA) != len(childrenB) {
+		unit, field := "children", ""
+		if _, ok := a.(*CallExpression); ok && countChildren(childrenA, "Arguments") != countChildren(childrenB, "Arguments") {
+			unit, field = "arguments", "Arguments"
+		}
+		return mismatch(path,
+			describe(a)+" with "+strconv.Itoa(countChildren(childrenA, field))+" "+unit,
+			describe(b)+" with "+strconv.Itoa(countChildren(childrenB, field))+" "+unit)
+	}
+
+	for i := range childrenA {
+		ca, cb := childrenA[i], childrenB[i]
+		if ca.field != cb.field || ca.index != cb.index {
+			return mismatch(path, describe(a), describe(b))
+		}
+		if d := diff(ca.node, cb.node, childPath(path, ca)); d != "" {
+			return d
+		}
+	}
+
+	// Children are equal, the difference is in the node itself,
+	// like a literal value or a keyword of TRIM
+	if a.String() != b.String() {
+		return mismatch(path, describe(a), describe(b))
+	}
+
+	return ""
+}
+
+// Counts the children in the field, or all children when field is empty,
+// the function name `Fn` of a call is never counted
+func countChildren(list []child, field string) int {
+	n := 0
+	for _, c := range list {
+		if c.field != "Fn" && (field == "" || c.field == field) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func operatorOf(expr Expression) string {
+	switch v := expr.(type) {
+	case *InfixExpression:
+		return string(v.Operator())
+	case *PrefixExpression:
+		return string(v.Token.Type)
+	case *CustomOperatorExpression:
+		return v.Operator
+	case *IsExpression:
+		return string(isToken(v.Negated).Type)
+	case *JSONAccessExpression:
+		return string(v.Operator())
+	default:
+		return ""
+	}
+}
+
+func childPath(path string, c child) string {
+	var b strings.Builder
+	b.WriteString(path)
+	if path != "" {
+		b.WriteString(".")
+	}
+	b.WriteString(c.field)
+	if c.index >= 0 {
+		b.WriteString("[" + strconv.Itoa(c.index) + "]")
+	}
+
+	return b.String()
+}
+
+func describe(expr Expression) string {
+	if expr == nil {
+		return "nil"
+	}
+
+	return fmt.Sprintf("%T %q", expr, expr.String())
+}
+
+func mismatch(path, expected, actual string) string {
+	if path == "" {
+		path = "root"
+	}
+
+	return "at " + path + ": expected " + expected + ", got " + actual
+}
@@ -0,0 +1,44 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+)
+
+func TestDiff(t *testing.T) {
+	type TestCase struct {
+		a    string
+		b    string
+		diff string
+	}
+
+	inputs := []TestCase{
+		{"a + b * c", "a + b * c", ""},
+		{"a and b", "a AND b", ""},
+		{"a + b * c", "a + b / c", `at Right: expected operator *, got operator /`},
+		{"a + b", "a - b", `at root: expected operator +, got operator -`},
+		{"f(x, 1)", "f(x, 2)", `at Arguments[1]: expected *ast.NumberLiteral "1", got *ast.NumberLiteral "2"`},
+		{"x = 'a'", "x = a", `at Right: expected *ast.StringLiteral "'a'", got *ast.Identifier "a"`},
+		{"f(x, y)", "f(x)", `at root: expected *ast.CallExpression "f(x, y)" with 2 arguments, got *ast.CallExpression "f(x)" with 1 arguments`},
+		{"f()", "f(x, y, z)", `at root: expected *ast.CallExpression "f()" with 0 arguments, got *ast.CallExpression "f(x, y, z)" with 3 arguments`},
+		{"(a, b)", "(a, b, c)", `at root: expected *ast.TupleExpression "(a, b)" with 2 children, got *ast.TupleExpression "(a, b, c)" with 3 children`},
+		{"TRIM(LEADING FROM s)", "TRIM(TRAILING FROM s)", `at root: expected *ast.TrimExpression "TRIM(LEADING FROM s)", got *ast.TrimExpression "TRIM(TRAILING FROM s)"`},
+		{"CASE WHEN a THEN f(1) END", "CASE WHEN a THEN f(2) END", `at Then[0].Arguments[0]: expected *ast.NumberLiteral "1", got *ast.NumberLiteral "2"`},
+		{"CASE x WHEN 1 THEN 2 END", "CASE WHEN 1 THEN 2 ELSE x END", `at root: expected *ast.CaseWhenExpression "CASE x WHEN 1 THEN 2 END", got *ast.CaseWhenExpression "CASE WHEN 1 THEN 2 ELSE x END"`},
+		{"CASE x WHEN 1 THEN 2 END", "CASE y WHEN 1 THEN 2 END", `at Operand: expected *ast.Identifier "x", got *ast.Identifier "y"`},
+	}
+	for _, input := range inputs {
+		d := ast.Diff(parseExpression(t, input.a), parseExpression(t, input.b))
+		if d != input.diff {
+			t.Errorf("Diff(%q, %q) not %q, got %q", input.a, input.b, input.diff, d)
+		}
+	}
+
+	if d := ast.Diff(nil, nil); d != "" {
+		t.Errorf("Diff(nil, nil) not empty, got %q", d)
+	}
+	if d := ast.Diff(parseExpression(t, "a"), nil); d != `at root: expected *ast.Identifier "a", got nil` {
+		t.Errorf("Diff(a, nil) got %q", d)
+	}
+}
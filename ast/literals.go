@@ -0,0 +1,32 @@
+package ast
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// LiteralValues returns the distinct decoded values of the string literals
+// and the raw literals of the numbers in the expression tree, in pre-order,
+// so `name = 'a' AND age > 18` gives `[a]` and `[18]`.
+// Hex and bit strings are not text and not reported.
+func LiteralValues(expr Expression) (strs []string, numbers []string) {
+	seenStrs := map[string]bool{}
+	seenNumbers := map[string]bool{}
+	Walk(expr, func(node Expression) bool {
+		switch v := node.(type) {
+		case *StringLiteral:
+			if v.Token.Type != token.STRING {
+				break
+			}
+			if s := v.Decode(); !seenStrs[s] {
+				seenStrs[s] = true
+				strs = append(strs, s)
+			}
+		case *NumberLiteral:
+			if !seenNumbers[v.Literal] {
+				seenNumbers[v.Literal] = true
+				numbers = append(numbers, v.Literal)
+			}
+		}
+		return true
+	})
+
+	return strs, numbers
+}
@@ -0,0 +1,133 @@
+package ast_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestLiteralValues(t *testing.T) {
+	type TestCase struct {
+		input   string
+		strs    []string
+		numbers []string
+	}
+
+	inputs := []TestCase{
+		{"name = 'a' AND age > 18 AND city IN ('x', 'y')", []string{"a", "x", "y"}, []string{"18"}},
+		{"a = 'it''s' OR b = 'it\\'s' OR c = 'x'", []string{"it's", "x"}, []string{}},
+		{"x BETWEEN 1 AND 2.5 OR x = 1 OR y = 0x1F", []string{}, []string{"1", "2.5", "0x1F"}},
+		{"f(x'4F', b'01', '')", []string{""}, []string{}},
+		{"a + b", []string{}, []string{}},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		strs, numbers := ast.LiteralValues(expr)
+		if len(strs) != len(input.strs) || (len(strs) > 0 && !reflect.DeepEqual(strs, input.strs)) {
+			t.Errorf("LiteralValues(%q) strings not %q, got %q", input.input, input.strs, strs)
+		}
+		if len(numbers) != len(input.numbers) || (len(numbers) > 0 && !reflect.DeepEqual(numbers, input.numbers)) {
+			t.Errorf("LiteralValues(%q) numbers not %q, got %q", input.input, input.numbers, numbers)
+		}
+	}
+}
+
+func TestStringLiteralDecode(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"'abc'", "abc"},
+		{"''", ""},
+		{"'it''s'", "it's"},
+		{"'it\\'s'", "it's"},
+		{"'a\\nb'", "a\\nb"},
+		{"''''", "'"},
+		{"x'4F'", "x'4F'"},
+		{"B'0101'", "B'0101'"},
+	}
+	for _, input := range inputs {
+		expr, ok := parseExpression(t, input.input).(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("%q is not *ast.StringLiteral", input.input)
+		}
+		if actual := expr.Decode(); actual != input.expected {
+			t.Errorf("Decode() of %s not %q, got %q", input.input, input.expected, actual)
+		}
+	}
+}
+
+func TestNumberLiteralValue(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected float64
+	}
+
+	inputs := []TestCase{
+		{"123", 123},
+		{"123.456", 123.456},
+		{".123", 0.123},
+		{"2e2", 200},
+		{"0.2e+3", 200},
+		{"1.23e-2", 0.0123},
+		{"0xFF", 255},
+		{"0b101", 5},
+		{"0755", 493},
+		{"010", 8},
+		{"0", 0},
+		{"0.5", 0.5},
+		{"18446744073709551616", 18446744073709551616},
+	}
+	for _, input := range inputs {
+		expr, ok := parseExpression(t, input.input).(*ast.NumberLiteral)
+		if !ok {
+			t.Fatalf("%q is not *ast.NumberLiteral", input.input)
+		}
+		actual, err := expr.Value()
+		if err != nil {
+			t.Errorf("Value() of %s error: %s", input.input, err)
+			continue
+		}
+		if actual != input.expected {
+			t.Errorf("Value() of %s not %v, got %v", input.input, input.expected, actual)
+		}
+	}
+
+	if _, err := (&ast.NumberLiteral{Token: token.Token{Type: token.NUMBER, Literal: "1e400"}}).Value(); !errors.Is(err, ast.ErrNumberRange) {
+		t.Errorf("Value() of 1e400 should be ast.ErrNumberRange, got %v", err)
+	}
+}
+
+func TestNumberLiteralIntValue(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected int64
+		ok       bool
+	}
+
+	inputs := []TestCase{
+		{"123", 123, true},
+		{"010", 8, true},
+		{"0777777777777777777777", 9223372036854775807, true},
+		{"01777777777777777777777", 0, false},
+		{"0", 0, true},
+		{"0xFF", 255, true},
+		{"0B101", 5, true},
+		{"9223372036854775807", 9223372036854775807, true},
+		{"9223372036854775808", 0, false},
+		{"1.5", 0, false},
+		{"2e2", 0, false},
+	}
+	for _, input := range inputs {
+		expr := &ast.NumberLiteral{Token: token.Token{Type: token.NUMBER, Literal: input.input}}
+		actual, ok := expr.IntValue()
+		if actual != input.expected || ok != input.ok {
+			t.Errorf("IntValue() of %s not %d %t, got %d %t", input.input, input.expected, input.ok, actual, ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package ast
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// Negate returns a copy of the predicate negated, with the negation pushed inward:
+//
+//   - `NOT x` becomes `x`
+//   - `a AND b` becomes `(NOT a) OR (NOT b)` and `a OR b` becomes `(NOT a) AND (NOT b)`,
+//     negating both sides recursively (De Morgan's laws)
+//   - comparisons are inverted, `=` becomes `<>`, `<>` and `!=` become `=`,
+//     `<` becomes `>=`, `<=` becomes `>`, `>` becomes `<=` and `>=` becomes `<`
+//   - `IN`, `LIKE`, `IS` and `BETWEEN` swap with their `NOT` forms,
+//     as do `IS [NOT] NORMALIZED` and `IS [NOT] OF`
+//   - `TRUE` and `FALSE` swap
+//
+// All of these hold under SQL's three-valued logic.
+// Any other expression, like `a <=> b` or `x LIKE ANY (...)`, is wrapped in `NOT`.
+func Negate(expr Expression) Expression {
+	switch v := expr.(type) {
+	case *PrefixExpression:
+		if v.Token.Type == token.NOT {
+			return Clone(v.Right)
+		}
+	case *InfixExpression:
+		switch v.Operator() {
+		case token.AND:
+			return newInfix(token.OR, Negate(v.Left), Negate(v.Right))
+		case token.OR:
+			return newInfix(token.AND, Negate(v.Left), Negate(v.Right))
+		}
+		if inverted, ok := invertedOperators[v.Operator()]; ok {
+			return newInfix(inverted, Clone(v.Left), Clone(v.Right))
+		}
+	case *BetweenExpression:
+		return &NotBetweenExpression{Left: Clone(v.Left), Range: Clone(v.Range)}
+	case *NotBetweenExpression:
+		return &BetweenExpression{Left: Clone(v.Left), Range: Clone(v.Range)}
+	case *IsExpression:
+		c := Clone(v).(*IsExpression)
+		c.Negated = !c.Negated
+		c.Token = isToken(c.Negated)
+		return c
+	case *IsNormalizedExpression:
+		c := Clone(v).(*IsNormalizedExpression)
+		c.Negated = !c.Negated
+		c.Token = isToken(c.Negated)
+		return c
+	case *IsDocumentExpression:
+		c := Clone(v).(*IsDocumentExpression)
+		c.Negated = !c.Negated
+		c.Token = isToken(c.Negated)
+		return c
+	case *IsASetExpression:
+		c := Clone(v).(*IsASetExpression)
+		c.Negated = !c.Negated
+		c.Token = isToken(c.Negated)
+		return c
+	case *IsOfExpression:
+		c := Clone(v).(*IsOfExpression)
+		c.Negated = !c.Negated
+		c.Token = isToken(c.Negated)
+		return c
+	case *BooleanLiteral:
+		if v.Value() {
+			return &BooleanLiteral{Token: token.Token{Type: token.FALSE, Literal: token.FALSE}}
+		}
+		return &BooleanLiteral{Token: token.Token{Type: token.TRUE, Literal: token.TRUE}}
+	}
+
+	return &PrefixExpression{
+		Token: token.Token{Type: token.NOT, Literal: token.NOT},
+		Right: Clone(expr),
+	}
+}
+
+var invertedOperators = map[token.Type]token.Type{
+	token.EQ:       token.NOT_EQ,
+	token.NOT_EQ:   token.EQ,
+	token.BANG_EQ:  token.EQ,
+	token.LT:       token.GT_EQ,
+	token.LT_EQ:    token.GT,
+	token.GT:       token.LT_EQ,
+	token.GT_EQ:    token.LT,
+	token.IN:       token.NOT_IN,
+	token.NOT_IN:   token.IN,
+	token.LIKE:     token.NOT_LIKE,
+	token.NOT_LIKE: token.LIKE,
+	token.IS:       token.IS_NOT,
+	token.IS_NOT:   token.IS,
+}
+
+func newInfix(op token.Type, left, right Expression) *InfixExpression {
+	return &InfixExpression{
+		Token: token.Token{Type: op, Literal: string(op)},
+		Left:  left,
+		Right: right,
+	}
+}
+
+func isToken(negated bool) token.Token {
+	if negated {
+		return token.Token{Type: token.IS_NOT, Literal: token.IS_NOT}
+	}
+
+	return token.Token{Type: token.IS, Literal: token.IS}
+}
@@ -0,0 +1,95 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestNegate(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"NOT x", "x"},
+		{"NOT (a AND b)", "(a AND b)"},
+		{"a AND b", "((NOT a) OR (NOT b))"},
+		{"a OR b", "((NOT a) AND (NOT b))"},
+		{"a = 1 AND (b < 2 OR NOT c)", "((a <> 1) OR ((b >= 2) AND c))"},
+		{"a = b", "(a <> b)"},
+		{"a <> b", "(a = b)"},
+		{"a != b", "(a = b)"},
+		{"a < b", "(a >= b)"},
+		{"a <= b", "(a > b)"},
+		{"a > b", "(a <= b)"},
+		{"a >= b", "(a < b)"},
+		{"a IN (1, 2)", "(a NOT IN (1, 2))"},
+		{"a NOT IN (1, 2)", "(a IN (1, 2))"},
+		{"a LIKE 'x%'", "(a NOT LIKE 'x%')"},
+		{"a NOT LIKE 'x%'", "(a LIKE 'x%')"},
+		{"a IS NULL", "(a IS NOT NULL)"},
+		{"a IS NOT NULL", "(a IS NULL)"},
+		{"a BETWEEN 1 AND 2", "(a NOT BETWEEN (1 AND 2))"},
+		{"a NOT BETWEEN 1 AND 2", "(a BETWEEN (1 AND 2))"},
+		{"a IS NORMALIZED", "(a IS NOT NORMALIZED)"},
+		{"a IS NOT NORMALIZED NFC", "(a IS NORMALIZED NFC)"},
+		{"a IS OF (INT)", "(a IS NOT OF (INT))"},
+		{"TRUE", "FALSE"},
+		{"false", "TRUE"},
+		// Not invertible
+		{"a <=> b", "(NOT (a <=> b))"},
+		{"a LIKE ANY ('x%')", "(NOT (a LIKE ANY ('x%')))"},
+		{"f(x)", "(NOT f(x))"},
+		{"a", "(NOT a)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		before := expr.String()
+		actual := ast.Negate(expr)
+		if actual.String() != input.expected {
+			t.Errorf("Negate(%q) not %q, got %q", input.input, input.expected, actual.String())
+		}
+		if expr.String() != before {
+			t.Errorf("Negate(%q) modified its input, got %q", input.input, expr.String())
+		}
+	}
+}
+
+func TestNegateIsDocument(t *testing.T) {
+	expr := &ast.IsDocumentExpression{
+		Token: token.Token{Type: token.IS, Literal: "IS"},
+		Expr:  &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+	}
+
+	negated := ast.Negate(expr)
+	if negated.String() != "(x IS NOT DOCUMENT)" {
+		t.Errorf("Negate() not %q, got %q", "(x IS NOT DOCUMENT)", negated.String())
+	}
+	if actual := ast.Negate(negated).String(); actual != "(x IS DOCUMENT)" {
+		t.Errorf("Negate() twice not %q, got %q", "(x IS DOCUMENT)", actual)
+	}
+	if expr.Negated {
+		t.Errorf("Negate() modified its input")
+	}
+}
+
+func TestNegateIsASet(t *testing.T) {
+	expr := &ast.IsASetExpression{
+		Token: token.Token{Type: token.IS, Literal: "IS"},
+		Expr:  &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+	}
+
+	negated := ast.Negate(expr)
+	if negated.String() != "(x IS NOT A SET)" {
+		t.Errorf("Negate() not %q, got %q", "(x IS NOT A SET)", negated.String())
+	}
+	if actual := ast.Negate(negated).String(); actual != "(x IS A SET)" {
+		t.Errorf("Negate() twice not %q, got %q", "(x IS A SET)", actual)
+	}
+	if expr.Negated {
+		t.Errorf("Negate() modified its input")
+	}
+}
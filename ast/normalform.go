@@ -0,0 +1,116 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+// MaxNormalFormLiterals bounds the number of literals in the result of `ToCNF` and `ToDNF`,
+// distributing AND over OR (or OR over AND) can grow the expression exponentially
+const MaxNormalFormLiterals = 4096
+
+// ToCNF returns a copy of the predicate in conjunctive normal form,
+// an AND of ORs of literals, like `(a OR b) AND (a OR c)` for `a OR (b AND c)`.
+//
+// NOT is pushed inward with `Negate`, anything other than AND, OR and NOT is a literal.
+// An error is returned if the result would have more than `MaxNormalFormLiterals` literals.
+func ToCNF(expr Expression) (Expression, error) {
+	clauses, err := normalForm(negationNormalForm(expr), token.AND)
+	if err != nil {
+		return nil, err
+	}
+
+	return joinClauses(clauses, token.AND, token.OR), nil
+}
+
+// ToDNF returns a copy of the predicate in disjunctive normal form,
+// an OR of ANDs of literals, like `(a AND b) OR (a AND c)` for `a AND (b OR c)`, see `ToCNF`
+func ToDNF(expr Expression) (Expression, error) {
+	clauses, err := normalForm(negationNormalForm(expr), token.OR)
+	if err != nil {
+		return nil, err
+	}
+
+	return joinClauses(clauses, token.OR, token.AND), nil
+}
+
+// Pushes NOT inward until it only applies to literals
+func negationNormalForm(expr Expression) Expression {
+	if v, ok := expr.(*PrefixExpression); ok && v.Token.Type == token.NOT {
+		expr = Negate(v.Right)
+		if v, ok := expr.(*PrefixExpression); ok && v.Token.Type == token.NOT {
+			return expr
+		}
+	}
+
+	if v, ok := expr.(*InfixExpression); ok && (v.Operator() == token.AND || v.Operator() == token.OR) {
+		return newInfix(v.Operator(), negationNormalForm(v.Left), negationNormalForm(v.Right))
+	}
+
+	return Clone(expr)
+}
+
+// Returns the clauses joined by outer, each clause is a list of literals joined by the other operator
+func normalForm(expr Expression, outer token.Type) ([][]Expression, error) {
+	v, ok := expr.(*InfixExpression)
+	if !ok || (v.Operator() != token.AND && v.Operator() != token.OR) {
+		return [][]Expression{{expr}}, nil
+	}
+
+	left, err := normalForm(v.Left, outer)
+	if err != nil {
+		return nil, err
+	}
+	right, err := normalForm(v.Right, outer)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Operator() == outer {
+		return append(left, right...), nil
+	}
+
+	// Distribute, (a1 AND a2) OR (b1 AND b2) => (a1 OR b1) AND (a1 OR b2) AND ...
+	var (
+		clauses  [][]Expression
+		literals int
+	)
+	for _, l := range left {
+		for _, r := range right {
+			literals += len(l) + len(r)
+			if literals > MaxNormalFormLiterals {
+				return nil, fmt.Errorf("normal form exceeds %d literals", MaxNormalFormLiterals)
+			}
+
+			clause := make([]Expression, 0, len(l)+len(r))
+			clause = append(clause, l...)
+			clause = append(clause, r...)
+			clauses = append(clauses, clause)
+		}
+	}
+
+	return clauses, nil
+}
+
+func joinClauses(clauses [][]Expression, outer, inner token.Type) Expression {
+	var result Expression
+	for _, clause := range clauses {
+		var joined Expression
+		for _, literal := range clause {
+			if joined == nil {
+				joined = Clone(literal)
+			} else {
+				joined = newInfix(inner, joined, Clone(literal))
+			}
+		}
+
+		if result == nil {
+			result = joined
+		} else {
+			result = newInfix(outer, result, joined)
+		}
+	}
+
+	return result
+}
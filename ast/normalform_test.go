@@ -0,0 +1,85 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+)
+
+func TestToCNF(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"(a OR b) AND c", "((a OR b) AND c)"},
+		{"a OR (b AND c)", "((a OR b) AND (a OR c))"},
+		{"(a AND b) OR (c AND d)", "((((a OR c) AND (a OR d)) AND (b OR c)) AND (b OR d))"},
+		{"NOT (a OR b)", "((NOT a) AND (NOT b))"},
+		{"NOT (x = 1 AND y > 2) OR z", "(((x <> 1) OR (y <= 2)) OR z)"},
+		{"NOT NOT (a OR (b AND c))", "((a OR b) AND (a OR c))"},
+		{"a", "a"},
+		{"NOT a", "(NOT a)"},
+		{"f(a AND b) OR c", "(f((a AND b)) OR c)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		before := expr.String()
+		actual, err := ast.ToCNF(expr)
+		if err != nil {
+			t.Fatalf("ToCNF(%q) failed: %s", input.input, err)
+		}
+		if actual.String() != input.expected {
+			t.Errorf("ToCNF(%q) not %q, got %q", input.input, input.expected, actual.String())
+		}
+		if expr.String() != before {
+			t.Errorf("ToCNF(%q) modified the input, got %q", input.input, expr.String())
+		}
+	}
+}
+
+func TestToDNF(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"a AND (b OR c)", "((a AND b) OR (a AND c))"},
+		{"(a OR b) AND c", "((a AND c) OR (b AND c))"},
+		{"a OR (b AND c)", "(a OR (b AND c))"},
+		// AND and OR bind equally from left to right
+		{"a OR b AND c", "((a AND c) OR (b AND c))"},
+		{"NOT (a AND b)", "((NOT a) OR (NOT b))"},
+		{"NOT (a OR b) AND c", "(((NOT a) AND (NOT b)) AND c)"},
+		{"x BETWEEN 1 AND 2 AND (y OR z)", "(((x BETWEEN (1 AND 2)) AND y) OR ((x BETWEEN (1 AND 2)) AND z))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		actual, err := ast.ToDNF(expr)
+		if err != nil {
+			t.Fatalf("ToDNF(%q) failed: %s", input.input, err)
+		}
+		if actual.String() != input.expected {
+			t.Errorf("ToDNF(%q) not %q, got %q", input.input, input.expected, actual.String())
+		}
+	}
+}
+
+func TestNormalFormTooLarge(t *testing.T) {
+	// (a0 OR b0) AND (a1 OR b1) AND ... has 2^n clauses in DNF
+	terms := make([]string, 16)
+	for i := range terms {
+		terms[i] = "(a OR b)"
+	}
+	expr := parseExpression(t, strings.Join(terms, " AND "))
+
+	if _, err := ast.ToDNF(expr); err == nil {
+		t.Errorf("ToDNF() should exceed the limit")
+	}
+	if _, err := ast.ToCNF(expr); err != nil {
+		t.Errorf("ToCNF() failed: %s", err)
+	}
+}
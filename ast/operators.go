@@ -0,0 +1,76 @@
+package ast
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// Operators returns the distinct operator token types used in the expression tree,
+// in the order they are first seen in pre-order, so `a + b * c AND d` gives `[AND + *]`.
+//
+// Infix and prefix operators are reported with their token type, like `token.NOT_IN`,
+// and the dedicated operator nodes with their keyword or symbol:
+// `BETWEEN`, `NOT BETWEEN`, `LIKE` / `NOT LIKE`, `ESCAPE`, `IS` / `IS NOT`, `COLLATE`, `OPERATOR`,
+// `->` / `->>` of JSON access and lambdas, `::` casts and the `?` of ternaries.
+// The IS predicates `NORMALIZED`, `OF`, `DOCUMENT` and `A` of `IS A SET` are reported with their `IS` / `IS NOT`.
+// The `AND` of a BETWEEN range is part of the BETWEEN syntax and not reported.
+func Operators(expr Expression) []token.Type {
+	var operators []token.Type
+	seen := map[token.Type]bool{}
+	add := func(t token.Type) {
+		if !seen[t] {
+			seen[t] = true
+			operators = append(operators, t)
+		}
+	}
+
+	betweenRanges := map[Expression]bool{}
+	Walk(expr, func(node Expression) bool {
+		switch v := node.(type) {
+		case *InfixExpression:
+			if !betweenRanges[v] {
+				add(v.Operator())
+			}
+		case *PrefixExpression:
+			add(v.Token.Type)
+		case *BetweenExpression:
+			add(token.BETWEEN)
+			betweenRanges[v.Range] = true
+		case *NotBetweenExpression:
+			add(token.NOT_BETWEEN)
+			betweenRanges[v.Range] = true
+		case *LikeExpression:
+			add(v.Operator())
+		case *EscapeExpression:
+			add(token.ESCAPE)
+		case *IsExpression:
+			add(isToken(v.Negated).Type)
+		case *IsNormalizedExpression:
+			add(isToken(v.Negated).Type)
+			add(token.NORMALIZED)
+		case *IsOfExpression:
+			add(isToken(v.Negated).Type)
+			add(token.OF)
+		case *IsDocumentExpression:
+			add(isToken(v.Negated).Type)
+			add(token.DOCUMENT)
+		case *IsASetExpression:
+			add(isToken(v.Negated).Type)
+			add(token.A)
+		case *CollateExpression:
+			add(token.COLLATE)
+		case *CustomOperatorExpression:
+			add(token.OPERATOR)
+		case *JSONAccessExpression:
+			add(v.Operator())
+		case *LambdaExpression:
+			add(v.Token.Type)
+		case *TernaryExpression:
+			add(v.Token.Type)
+		case *CastExpression:
+			if v.IsDoubleColon() {
+				add(token.COLON2)
+			}
+		}
+		return true
+	})
+
+	return operators
+}
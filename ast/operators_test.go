@@ -0,0 +1,59 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/lexer"
+	"github.com/chenjunwen186/sqlexpr/parser"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestOperators(t *testing.T) {
+	type TestCase struct {
+		input     string
+		operators []token.Type
+	}
+
+	inputs := []TestCase{
+		{"a + b * c AND d", []token.Type{token.AND, token.PLUS, token.ASTERISK}},
+		{"a + b + -c", []token.Type{token.PLUS, token.MINUS}},
+		{"NOT a IN (1, 2) OR b NOT LIKE 'x%'", []token.Type{token.OR, token.NOT, token.IN, token.NOT_LIKE}},
+		{"x BETWEEN 1 AND 2 AND y NOT BETWEEN a + 1 AND 3", []token.Type{token.AND, token.BETWEEN, token.NOT_BETWEEN, token.PLUS}},
+		{"f(s COLLATE nocase, ~x)", []token.Type{token.COLLATE, token.TILDE}},
+		{"a", nil},
+		{"data -> 'a' = 1", []token.Type{token.EQ, token.PRT}},
+		{"data ->> 'a'", []token.Type{token.PRT2}},
+		{"x::int + 1", []token.Type{token.PLUS, token.COLON2}},
+		{"CAST(x AS int) + 1", []token.Type{token.PLUS}},
+		{"a ? b : c", []token.Type{token.QUESTION}},
+		{"a LIKE 'x!%' ESCAPE '!'", []token.Type{token.LIKE, token.ESCAPE}},
+		{"x IS NOT NORMALIZED NFC", []token.Type{token.IS_NOT, token.NORMALIZED}},
+		{"x IS OF (int) OR y IS NULL", []token.Type{token.OR, token.IS, token.OF}},
+	}
+	for _, input := range inputs {
+		operators := ast.Operators(parseExpression(t, input.input))
+		if !reflect.DeepEqual(operators, input.operators) {
+			t.Errorf("Operators(%q) not %v, got %v", input.input, input.operators, operators)
+		}
+	}
+
+	// The nodes only parsed with options
+	opts := parser.Options{Lambdas: true, XMLPredicates: true, OracleSetPredicates: true}
+	optionInputs := []TestCase{
+		{"arrayFilter(x -> x > 1, arr)", []token.Type{token.PRT, token.GT}},
+		{"x IS DOCUMENT", []token.Type{token.IS, token.DOCUMENT}},
+		{"x IS NOT A SET", []token.Type{token.IS_NOT, token.A}},
+	}
+	for _, input := range optionInputs {
+		expr, err := parser.NewWithOptions(lexer.New(input.input), opts).ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
+		}
+		operators := ast.Operators(expr)
+		if !reflect.DeepEqual(operators, input.operators) {
+			t.Errorf("Operators(%q) not %v, got %v", input.input, input.operators, operators)
+		}
+	}
+}
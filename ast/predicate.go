@@ -0,0 +1,167 @@
+package ast
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// IsPredicate reports whether the top-level expression is boolean-typed,
+// so it can be used as a filter like a WHERE clause.
+// Identifiers are treated as predicates by convention,
+// because their type is unknown without a schema.
+func IsPredicate(expr Expression) bool {
+	switch v := expr.(type) {
+	case *BooleanLiteral, *Identifier, *BetweenExpression, *NotBetweenExpression, *LikeExpression,
+		*IsExpression, *IsNormalizedExpression, *IsOfExpression, *IsDocumentExpression, *IsASetExpression:
+		return true
+	case *InfixExpression:
+		return isPredicateOperator(v.Operator())
+	case *PrefixExpression:
+		return v.Token.Type == token.NOT && IsPredicate(v.Right)
+	case *CaseWhenExpression:
+		for _, when := range v.Whens {
+			if !IsPredicate(when.Then) {
+				return false
+			}
+		}
+		return v.Else == nil || IsPredicate(v.Else)
+	default:
+		return false
+	}
+}
+
+func isPredicateOperator(t token.Type) bool {
+	switch t {
+	case token.AND, token.OR,
+		token.EQ, token.BANG_EQ, token.NOT_EQ, token.LT_EQ_GT,
+		token.LT, token.LT_EQ, token.GT, token.GT_EQ, token.BANG_LT, token.BANG_GT,
+		token.IN, token.NOT_IN, token.LIKE, token.NOT_LIKE, token.IS, token.IS_NOT,
+		token.CONTAINS, token.PRECEDES, token.SUCCEEDS, token.IMMEDIATELY_PRECEDES, token.IMMEDIATELY_SUCCEEDS:
+		return true
+	default:
+		return false
+	}
+}
+
+// Predicate is a simple `column <op> literal` comparison,
+// the column may be qualified like `t.a`
+type Predicate struct {
+	Column string
+	Op     token.Type
+	Value  Expression
+}
+
+// ExtractPredicates splits the top-level `AND` chain of expr
+// and returns the simple `column <op> literal` comparisons found in it,
+// in source order. `literal <op> column` is normalized by flipping the operator.
+// Other conjuncts are skipped.
+func ExtractPredicates(expr Expression) []Predicate {
+	var predicates []Predicate
+	for _, conjunct := range conjuncts(expr) {
+		if p, ok := simplePredicate(conjunct); ok {
+			predicates = append(predicates, p)
+		}
+	}
+
+	return predicates
+}
+
+// EquiConditions splits the top-level `AND` chain of expr like `ExtractPredicates`
+// and returns the `=` comparisons whose sides both reference a column,
+// like the join keys `a_id = b_id` or `lower(a) = lower(b)`, as [left, right] pairs in source order.
+// Comparisons with a literal or constant side, like `a = 1`, are skipped.
+func EquiConditions(expr Expression) [][2]Expression {
+	var pairs [][2]Expression
+	for _, conjunct := range conjuncts(expr) {
+		v, ok := conjunct.(*InfixExpression)
+		if !ok || v.Operator() != token.EQ {
+			continue
+		}
+		if referencesColumn(v.Left) && referencesColumn(v.Right) {
+			pairs = append(pairs, [2]Expression{v.Left, v.Right})
+		}
+	}
+
+	return pairs
+}
+
+// Reports whether the expression has an identifier in value position,
+// function names, argument names, collations and aliases are not columns
+func referencesColumn(expr Expression) bool {
+	found := false
+	WalkContext(expr, func(node, _ Expression, field string, _ int) bool {
+		if _, ok := node.(*Identifier); ok {
+			switch field {
+			case "Fn", "Name", "Collation", "Alias":
+			default:
+				found = true
+			}
+		}
+		return !found
+	})
+
+	return found
+}
+
+// Flattens a top-level `AND` chain
+func conjuncts(expr Expression) []Expression {
+	if v, ok := expr.(*InfixExpression); ok && v.Operator() == token.AND {
+		return append(conjuncts(v.Left), conjuncts(v.Right)...)
+	}
+
+	return []Expression{expr}
+}
+
+var flippedComparisons = map[token.Type]token.Type{
+	token.EQ:       token.EQ,
+	token.BANG_EQ:  token.BANG_EQ,
+	token.NOT_EQ:   token.NOT_EQ,
+	token.LT_EQ_GT: token.LT_EQ_GT,
+	token.LT:       token.GT,
+	token.LT_EQ:    token.GT_EQ,
+	token.GT:       token.LT,
+	token.GT_EQ:    token.LT_EQ,
+}
+
+func simplePredicate(expr Expression) (Predicate, bool) {
+	v, ok := expr.(*InfixExpression)
+	if !ok {
+		return Predicate{}, false
+	}
+
+	flipped, ok := flippedComparisons[v.Operator()]
+	if !ok {
+		return Predicate{}, false
+	}
+
+	if column, ok := predicateColumn(v.Left); ok && isLiteral(v.Right) {
+		return Predicate{Column: column, Op: v.Operator(), Value: v.Right}, true
+	}
+	if column, ok := predicateColumn(v.Right); ok && isLiteral(v.Left) {
+		return Predicate{Column: column, Op: flipped, Value: v.Left}, true
+	}
+
+	return Predicate{}, false
+}
+
+// The column name of a plain or qualified identifier, dotted like `t.a`
+func predicateColumn(expr Expression) (string, bool) {
+	switch v := expr.(type) {
+	case *Identifier:
+		return v.Value, true
+	case *QualifiedIdentifier:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// Signed numbers like `-1` are literals too
+func isLiteral(expr Expression) bool {
+	switch v := expr.(type) {
+	case *StringLiteral, *NumberLiteral, *BooleanLiteral, *NullLiteral:
+		return true
+	case *PrefixExpression:
+		_, ok := v.Right.(*NumberLiteral)
+		return ok && (v.Token.Type == token.MINUS || v.Token.Type == token.PLUS)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/lexer"
+	"github.com/chenjunwen186/sqlexpr/parser"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func parseExpression(t *testing.T, input string) ast.Expression {
+	l := lexer.New(input)
+	p := parser.New(l)
+	r, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("parseExpression(%q) failed: %s", input, err)
+	}
+
+	return r
+}
+
+func TestIsPredicate(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected bool
+	}
+
+	inputs := []TestCase{
+		{"a = 1", true},
+		{"a <> b", true},
+		{"a >= 1 AND b < 2", true},
+		{"a OR b", true},
+		{"a IN (1, 2)", true},
+		{"a NOT LIKE 'x%'", true},
+		{"a LIKE ANY ('x%', 'y%')", true},
+		{"a IS NULL", true},
+		{"a BETWEEN 1 AND 2", true},
+		{"a NOT BETWEEN 1 AND 2", true},
+		{"TRUE", true},
+		{"NOT a = 1", true},
+		{"NOT (a + 1)", false},
+		{"active", true},
+		{"CASE WHEN a THEN b = 1 ELSE FALSE END", true},
+		{"a + b", false},
+		{"a * 2", false},
+		{"'hello'", false},
+		{"123", false},
+		{"NULL", false},
+		{"-a", false},
+		{"f(a)", false},
+		{"CASE WHEN a THEN 1 ELSE 0 END", false},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if actual := ast.IsPredicate(expr); actual != input.expected {
+			t.Errorf("IsPredicate(%q) not %t, got %t", input.input, input.expected, actual)
+		}
+	}
+}
+
+func TestExtractPredicates(t *testing.T) {
+	type Expected struct {
+		column string
+		op     token.Type
+		value  string
+	}
+
+	type TestCase struct {
+		input    string
+		expected []Expected
+	}
+
+	inputs := []TestCase{
+		{
+			"a = 1 AND b > 2 AND f(c) < 3",
+			[]Expected{{"a", token.EQ, "1"}, {"b", token.GT, "2"}},
+		},
+		{
+			"name <> 'x' AND 10 <= age AND (a OR b = 1) AND c = d",
+			[]Expected{{"name", token.NOT_EQ, "'x'"}, {"age", token.GT_EQ, "10"}},
+		},
+		{
+			"a = 1 OR b = 2",
+			nil,
+		},
+		{
+			"(a = 1 AND b = 2) AND c != TRUE",
+			[]Expected{{"a", token.EQ, "1"}, {"b", token.EQ, "2"}, {"c", token.BANG_EQ, "TRUE"}},
+		},
+		{
+			"a + 1 = 2 AND a IN (1, 2)",
+			nil,
+		},
+		{
+			"t.a = 1 AND 2 < s.t.b AND t.a = t.b",
+			[]Expected{{"t.a", token.EQ, "1"}, {"s.t.b", token.GT, "2"}},
+		},
+		{
+			"a = -1 AND +2.5 >= b AND c = -d AND e = -(1 + 2)",
+			[]Expected{{"a", token.EQ, "(-1)"}, {"b", token.LT_EQ, "(+2.5)"}},
+		},
+	}
+	for _, input := range inputs {
+		predicates := ast.ExtractPredicates(parseExpression(t, input.input))
+		if len(predicates) != len(input.expected) {
+			t.Errorf("%q: len(predicates) not %d, got %d", input.input, len(input.expected), len(predicates))
+			continue
+		}
+		for i, e := range input.expected {
+			p := predicates[i]
+			if p.Column != e.column || p.Op != e.op || p.Value.String() != e.value {
+				t.Errorf("%q: predicates[%d] not {%s %s %s}, got {%s %s %s}", input.input, i, e.column, e.op, e.value, p.Column, p.Op, p.Value)
+			}
+		}
+	}
+}
+
+func TestEquiConditions(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected [][2]string
+	}
+
+	inputs := []TestCase{
+		{"a_id = b_id AND v > 1", [][2]string{{"a_id", "b_id"}}},
+		{"a_id = b_id AND lower(a_name) = lower(b_name) AND c = 1", [][2]string{{"a_id", "b_id"}, {"lower(a_name)", "lower(b_name)"}}},
+		{"x = y + 1 AND 'a' = z", [][2]string{{"x", "(y + 1)"}}},
+		{"a_id = b_id OR c = d", nil},
+		{"(a = b) AND (c = d)", [][2]string{{"a", "b"}, {"c", "d"}}},
+		{"a <> b AND a < b", nil},
+		{"now() = today() AND 1 = 1", nil},
+		{"NOT a = b", nil},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		pairs := ast.EquiConditions(expr)
+		if len(pairs) != len(input.expected) {
+			t.Fatalf("EquiConditions(%q) length not %d, got %d", input.input, len(input.expected), len(pairs))
+		}
+		for i, pair := range pairs {
+			if pair[0].String() != input.expected[i][0] || pair[1].String() != input.expected[i][1] {
+				t.Errorf("EquiConditions(%q)[%d] not %q, got [%q %q]", input.input, i, input.expected[i], pair[0].String(), pair[1].String())
+			}
+		}
+	}
+}
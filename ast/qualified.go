@@ -0,0 +1,46 @@
+package ast
+
+import "fmt"
+
+// RequireQualified returns an error naming the first bare column reference,
+// like `c` in `a.b + c`, to enforce `table.column` references.
+// Function names, argument names, collations, aliases and lambda parameters are not columns,
+// neither are the uses of a lambda parameter in its body.
+func RequireQualified(expr Expression) error {
+	return requireQualified(expr, nil)
+}
+
+func requireQualified(expr Expression, params map[string]bool) error {
+	var err error
+	WalkContext(expr, func(node, _ Expression, field string, _ int) bool {
+		if err != nil {
+			return false
+		}
+
+		switch v := node.(type) {
+		case *QualifiedIdentifier, *QualifiedWildcard:
+			return false
+		case *LambdaExpression:
+			scope := make(map[string]bool, len(params)+len(v.Params))
+			for name := range params {
+				scope[name] = true
+			}
+			for _, param := range v.Params {
+				scope[param.Value] = true
+			}
+			err = requireQualified(v.Body, scope)
+			return false
+		case *Identifier:
+			switch field {
+			case "Fn", "Name", "Collation", "Alias":
+			default:
+				if !params[v.Value] {
+					err = fmt.Errorf("identifier %s is not qualified, like table.%s", v.Value, v.Value)
+				}
+			}
+		}
+		return err == nil
+	})
+
+	return err
+}
@@ -0,0 +1,63 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/lexer"
+	"github.com/chenjunwen186/sqlexpr/parser"
+)
+
+func TestRequireQualified(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"a.b + d.e", ""},
+		{"a.b + c", "identifier c is not qualified, like table.c"},
+		{"c + a.b", "identifier c is not qualified, like table.c"},
+		{"x AND y", "identifier x is not qualified, like table.x"},
+		{"s.t.c = 1 AND u.v IN (1, 2)", ""},
+		{"lower(t.name) = 'a'", ""},
+		{"s.lower(t.name) = 'a'", ""},
+		{"f(t.a, sep => t.b)", ""},
+		{"f(t.a, sep => b)", "identifier b is not qualified, like table.b"},
+		{"t.name COLLATE nocase = 'a'", ""},
+		{"COUNT(*) + COUNT(t.*)", ""},
+		{"CAST(t.a AS int) > 1", ""},
+		{"CASE t.a WHEN 1 THEN b END", "identifier b is not qualified, like table.b"},
+		{"1 + 'a'", ""},
+		{"COUNT(t.a) AS total", ""},
+	}
+	for _, input := range inputs {
+		err := ast.RequireQualified(parseExpression(t, input.input))
+		if input.err == "" && err != nil {
+			t.Errorf("RequireQualified(%q) failed: %s", input.input, err)
+		} else if input.err != "" && (err == nil || err.Error() != input.err) {
+			t.Errorf("RequireQualified(%q): err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	// Lambda parameters are not columns in their body
+	lambdas := []TestCase{
+		{"arrayFilter(x -> x > t.min, t.arr)", ""},
+		{"arrayMap((x, y) -> x + y, t.a, t.b)", ""},
+		{"arrayFilter(x -> x > y, t.arr)", "identifier y is not qualified, like table.y"},
+		{"arrayFilter(x -> x > 1, x)", "identifier x is not qualified, like table.x"},
+	}
+	for _, input := range lambdas {
+		p := parser.NewWithOptions(lexer.New(input.input), parser.Options{Lambdas: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
+		}
+		err = ast.RequireQualified(expr)
+		if input.err == "" && err != nil {
+			t.Errorf("RequireQualified(%q) failed: %s", input.input, err)
+		} else if input.err != "" && (err == nil || err.Error() != input.err) {
+			t.Errorf("RequireQualified(%q): err not %q, got %v", input.input, input.err, err)
+		}
+	}
+}
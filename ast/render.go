@@ -0,0 +1,645 @@
+package ast
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+type KeywordCase int
+
+const (
+	KeywordUpper    KeywordCase = iota // `AND`, `IS NULL`
+	KeywordLower                       // `and`, `is null`
+	KeywordPreserve                    // The input spelling where the tree keeps it, upper-case otherwise
+)
+
+type IdentifierQuote int
+
+const (
+	QuoteNone     IdentifierQuote = iota // col
+	QuoteBacktick                        // `col`
+	QuoteDouble                          // "col"
+	QuoteBracket                         // [col]
+)
+
+type Parentheses int
+
+const (
+	// Wrap every operator expression in parentheses like `String()` does
+	ParenthesesAlways Parentheses = iota
+	// Only add the parentheses needed to keep the grouping of the tree
+	// when the output is parsed again
+	ParenthesesMinimal
+)
+
+type RenderOptions struct {
+	KeywordCase     KeywordCase
+	IdentifierQuote IdentifierQuote
+	Parentheses     Parentheses
+
+	// Render infix operators with their input spelling, like `!=` parsed
+	// with an operator alias to `<>`, instead of their canonical symbol
+	OriginalOperators bool
+}
+
+// Render renders the expression as SQL with configurable keyword case,
+// identifier quoting and parentheses.
+// The zero options render like `String()` except for the keyword case of literals
+// like `null`, which `String()` keeps as written.
+//
+// Only identifiers in value position are quoted,
+// function names, collations and type names are rendered as written.
+func Render(expr Expression, opts RenderOptions) string {
+	if expr == nil {
+		return ""
+	}
+
+	r := &renderer{opts: opts}
+	r.render(expr)
+
+	return r.b.String()
+}
+
+type renderer struct {
+	opts RenderOptions
+	b    strings.Builder
+}
+
+// Binding strength of the nodes, as parsed by the parser
+const (
+	precAlias = iota + 1
+	precLambda
+	precTernary
+	precCond
+	precNot
+	precIn
+	precEquals
+	precLessGreater
+	precOther
+	precBitOr
+	precBitXor
+	precBitAnd
+	precShift
+	precSum
+	precProduct
+	precMod
+	precIs
+	precCollate
+	precPrefix
+	precCast
+	precJSONAccess
+	precAtom
+)
+
+var infixPrecedences = map[token.Type]int{
+	token.AND:           precCond,
+	token.OR:            precCond,
+	token.IN:            precIn,
+	token.NOT_IN:        precIn,
+	token.LIKE:          precIn,
+	token.NOT_LIKE:      precIn,
+	token.EQ:            precEquals,
+	token.BANG_EQ:       precEquals,
+	token.NOT_EQ:        precEquals,
+	token.LT_EQ_GT:      precLessGreater,
+	token.LT:            precLessGreater,
+	token.LT_EQ:         precLessGreater,
+	token.GT:            precLessGreater,
+	token.GT_EQ:         precLessGreater,
+	token.PIPE:          precBitOr,
+	token.XOR:           precBitXor,
+	token.AMP:           precBitAnd,
+	token.LT2:           precShift,
+	token.RT2:           precShift,
+	token.PLUS:          precSum,
+	token.MINUS:         precSum,
+	token.ASTERISK:      precProduct,
+	token.SLASH:         precProduct,
+	token.MOD:           precMod,
+	token.IS:            precIs,
+	token.IS_NOT:        precIs,
+	token.BETWEEN:       precIn,
+	token.NOT_BETWEEN:   precIn,
+	token.HASH_GT:       precOther,
+	token.HASH_GT2:      precOther,
+	token.AT_GT:         precOther,
+	token.LT_AT:         precOther,
+	token.AMP2:          precOther,
+	token.QUESTION:      precOther,
+	token.QUESTION_PIPE: precOther,
+	token.QUESTION_AMP:  precOther,
+
+	token.CONTAINS:             precEquals,
+	token.PRECEDES:             precEquals,
+	token.SUCCEEDS:             precEquals,
+	token.IMMEDIATELY_PRECEDES: precEquals,
+	token.IMMEDIATELY_SUCCEEDS: precEquals,
+}
+
+func precedence(expr Expression) int {
+	switch v := expr.(type) {
+	case *InfixExpression:
+		if p, ok := infixPrecedences[v.Operator()]; ok {
+			return p
+		}
+		return precCond
+	case *PrefixExpression:
+		if v.Token.Type == token.NOT {
+			return precNot
+		}
+		return precPrefix
+	case *BetweenExpression, *NotBetweenExpression, *LikeExpression:
+		return precIn
+	case *IsExpression, *IsNormalizedExpression, *IsOfExpression, *IsDocumentExpression, *IsASetExpression:
+		return precIs
+	case *CollateExpression:
+		return precCollate
+	case *CustomOperatorExpression:
+		return precOther
+	case *CastExpression:
+		if v.IsDoubleColon() {
+			return precCast
+		}
+		return precAtom
+	case *LambdaExpression:
+		return precLambda
+	case *JSONAccessExpression:
+		return precJSONAccess
+	case *TernaryExpression:
+		return precTernary
+	case *AliasExpression:
+		return precAlias
+	default:
+		return precAtom
+	}
+}
+
+func (r *renderer) write(s ...string) {
+	for _, v := range s {
+		r.b.WriteString(v)
+	}
+}
+
+// Renders a keyword, literal is its input spelling if known
+func (r *renderer) keyword(canonical, literal string) string {
+	switch r.opts.KeywordCase {
+	case KeywordLower:
+		return strings.ToLower(canonical)
+	case KeywordPreserve:
+		if strings.EqualFold(literal, canonical) {
+			return literal
+		}
+	}
+
+	return canonical
+}
+
+// Renders an operator symbol, which is a keyword like `IS NOT` if it has letters.
+// With OriginalOperators a symbolic input spelling like `!=` is kept as is,
+// a keyword spelling still follows KeywordCase.
+func (r *renderer) operator(symbol, literal string) string {
+	if r.opts.OriginalOperators && literal != "" {
+		if strings.IndexFunc(literal, unicode.IsLetter) < 0 {
+			return literal
+		}
+		symbol = strings.ToUpper(literal)
+	}
+	if strings.IndexFunc(symbol, unicode.IsLetter) < 0 {
+		return symbol
+	}
+
+	return r.keyword(symbol, literal)
+}
+
+func (r *renderer) identifier(name string) string {
+	switch r.opts.IdentifierQuote {
+	case QuoteBacktick:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case QuoteDouble:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	case QuoteBracket:
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	default:
+		return name
+	}
+}
+
+// Opens the parentheses of an operator expression, returns the closing function
+func (r *renderer) group() func() {
+	if r.opts.Parentheses != ParenthesesAlways {
+		return func() {}
+	}
+
+	r.write("(")
+	return func() { r.write(")") }
+}
+
+// Renders an operand which needs parentheses in ParenthesesMinimal mode
+// if it binds looser than minPrecedence
+func (r *renderer) operand(expr Expression, minPrecedence int) {
+	if r.opts.Parentheses == ParenthesesMinimal && precedence(expr) < minPrecedence {
+		r.write("(")
+		r.render(expr)
+		r.write(")")
+		return
+	}
+
+	r.render(expr)
+}
+
+func (r *renderer) list(exprs []Expression) {
+	for i, expr := range exprs {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.render(expr)
+	}
+}
+
+func (r *renderer) render(expr Expression) {
+	switch v := expr.(type) {
+	case *Identifier:
+		r.write(r.identifier(v.Value))
+	case *QualifiedIdentifier:
+		for i, part := range v.Parts {
+			if i > 0 {
+				r.write(token.PERIOD)
+			}
+			r.write(r.identifier(part.Value))
+		}
+	case *AliasExpression:
+		// Never grouped, `(x AS y)` is not valid SQL
+		r.operand(v.Expr, precAlias+1)
+		literal := ""
+		if v.Token.Type == token.AS {
+			literal = v.Token.Literal
+		}
+		r.write(" ", r.keyword(token.AS, literal), " ", r.identifier(v.Alias.Value))
+	case *Wildcard:
+		r.write(token.ASTERISK)
+	case *QualifiedWildcard:
+		r.write(r.identifier(v.Table.Value), token.PERIOD, token.ASTERISK)
+	case *NullLiteral:
+		r.write(r.keyword(token.NULL, v.Literal))
+	case *DefaultLiteral:
+		r.write(r.keyword(token.DEFAULT, v.Literal))
+	case *BooleanLiteral:
+		r.write(r.keyword(string(v.Type), v.Literal))
+	case *StringLiteral, *NumberLiteral:
+		r.write(v.String())
+	case *PrefixExpression:
+		r.renderPrefix(v)
+	case *InfixExpression:
+		r.renderInfix(v)
+	case *BetweenExpression:
+		r.renderBetween(v.Left, v.Range, token.BETWEEN)
+	case *NotBetweenExpression:
+		r.renderBetween(v.Left, v.Range, token.NOT_BETWEEN)
+	case *EscapeExpression:
+		r.operand(v.Pattern, precIn+1)
+		r.write(" ", r.keyword(token.ESCAPE, v.Token.Literal), " ")
+		r.operand(v.Escape, precIn+1)
+	case *LikeExpression:
+		closeGroup := r.group()
+		r.operand(v.Left, precIn)
+		r.write(" ", r.operator(string(v.Operator()), v.Token.Literal), " ", r.keyword(string(v.Quantifier), ""), " (")
+		r.list(v.Patterns)
+		r.write(")")
+		closeGroup()
+	case *CollateExpression:
+		closeGroup := r.group()
+		r.operand(v.Left, precCollate)
+		r.write(" ", r.keyword(token.COLLATE, v.Token.Literal), " ")
+		if v.Collation != nil {
+			r.write(v.Collation.Value)
+		}
+		closeGroup()
+	case *IsExpression:
+		closeGroup := r.group()
+		r.operand(v.Left, precIs)
+		op := token.IS
+		if v.Negated {
+			op = token.IS_NOT
+		}
+		r.write(" ", r.keyword(op, v.Token.Literal), " ", r.keyword(string(v.Predicate), v.PredicateLiteral))
+		closeGroup()
+	case *IsNormalizedExpression:
+		closeGroup := r.group()
+		r.operand(v.Expr, precIs)
+		op := token.IS
+		if v.Negated {
+			op = token.IS_NOT
+		}
+		r.write(" ", r.keyword(op, v.Token.Literal), " ", r.keyword(token.NORMALIZED, ""))
+		if v.Form != "" {
+			r.write(" ", v.Form)
+		}
+		closeGroup()
+	case *IsDocumentExpression:
+		closeGroup := r.group()
+		r.operand(v.Expr, precIs)
+		op := token.IS
+		if v.Negated {
+			op = token.IS_NOT
+		}
+		r.write(" ", r.keyword(op, v.Token.Literal), " ", r.keyword(token.DOCUMENT, ""))
+		closeGroup()
+	case *IsASetExpression:
+		closeGroup := r.group()
+		r.operand(v.Expr, precIs)
+		op := token.IS
+		if v.Negated {
+			op = token.IS_NOT
+		}
+		r.write(" ", r.keyword(op, v.Token.Literal), " ", r.keyword(token.A, ""), " ", r.keyword(token.SET, ""))
+		closeGroup()
+	case *IsOfExpression:
+		closeGroup := r.group()
+		r.operand(v.Expr, precIs)
+		op := token.IS
+		if v.Negated {
+			op = token.IS_NOT
+		}
+		r.write(" ", r.keyword(op, v.Token.Literal), " ", r.keyword(token.OF, ""), " (")
+		for i, typ := range v.Types {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.render(typ)
+		}
+		r.write(")")
+		closeGroup()
+	case *CustomOperatorExpression:
+		closeGroup := r.group()
+		r.operand(v.Left, precOther)
+		r.write(" ", r.keyword(token.OPERATOR, v.Token.Literal), "(", v.Operator, ") ")
+		r.operand(v.Right, precOther+1)
+		closeGroup()
+	case *TupleExpression:
+		r.write("(")
+		r.list(v.Expressions)
+		r.write(")")
+	case *ArrayLiteral:
+		if v.Typed {
+			r.write(r.keyword("ARRAY", v.Token.Literal))
+		}
+		r.write("[")
+		r.list(v.Elements)
+		r.write("]")
+	case *IntervalExpression:
+		r.write(r.keyword(token.INTERVAL, v.Token.Literal), " ")
+		r.render(v.Value)
+		r.write(" ", r.keyword(string(v.Unit), ""))
+	case *LambdaExpression:
+		closeGroup := r.group()
+		if len(v.Params) == 1 {
+			r.render(v.Params[0])
+		} else {
+			r.write("(")
+			for i, param := range v.Params {
+				if i > 0 {
+					r.write(", ")
+				}
+				r.render(param)
+			}
+			r.write(")")
+		}
+		r.write(" ", token.PRT, " ")
+		r.render(v.Body)
+		closeGroup()
+	case *TernaryExpression:
+		closeGroup := r.group()
+		r.operand(v.Cond, precTernary+1)
+		r.write(" ", token.QUESTION, " ")
+		r.render(v.True)
+		r.write(" ", token.COLON, " ")
+		r.operand(v.False, precTernary)
+		closeGroup()
+	case *JSONAccessExpression:
+		closeGroup := r.group()
+		r.operand(v.Left, precJSONAccess)
+		r.write(" ", string(v.Operator()), " ")
+		r.operand(v.Index, precAtom)
+		closeGroup()
+	case *IndexExpression:
+		r.operand(v.Left, precAtom)
+		r.write("[")
+		r.render(v.Index)
+		r.write("]")
+	case *CallExpression:
+		r.renderCall(v)
+	case *NamedArgument:
+		r.write(v.Name.Value, " ", token.EQ_GT, " ")
+		r.render(v.Value)
+	case *GroupingConstruct:
+		r.write(r.keyword(v.Kind, ""), "(")
+		r.list(v.Args)
+		r.write(")")
+	case *CaseWhenExpression:
+		r.write(r.keyword(token.CASE, v.Token.Literal))
+		if v.Operand != nil {
+			r.write(" ")
+			r.render(v.Operand)
+		}
+		for _, when := range v.Whens {
+			r.write(" ", r.keyword(token.WHEN, ""), " ")
+			r.render(when.Cond)
+			r.write(" ", r.keyword(token.THEN, ""), " ")
+			r.render(when.Then)
+		}
+		if v.Else != nil {
+			r.write(" ", r.keyword(token.ELSE, ""), " ")
+			r.render(v.Else)
+		}
+		r.write(" ", r.keyword(token.END, ""))
+	case *UnnestExpression:
+		r.write(r.keyword("UNNEST", ""), "(")
+		r.render(v.Array)
+		r.write(")")
+	case *PositionExpression:
+		r.write(r.keyword("POSITION", ""), "(")
+		r.operand(v.Substr, precIn+1)
+		r.write(" ", r.keyword(token.IN, ""), " ")
+		r.render(v.Str)
+		r.write(")")
+	case *TrimExpression:
+		r.write(r.keyword("TRIM", ""), "(")
+		if v.Spec != "" {
+			r.write(r.keyword(v.Spec, ""), " ")
+		}
+		if v.Chars != nil {
+			r.render(v.Chars)
+			r.write(" ")
+		}
+		r.write(r.keyword(token.FROM, ""), " ")
+		r.render(v.Source)
+		r.write(")")
+	case *SubstringExpression:
+		r.write(r.keyword("SUBSTRING", ""), "(")
+		r.render(v.Source)
+		if v.From != nil {
+			r.write(" ", r.keyword(token.FROM, ""), " ")
+			r.render(v.From)
+		}
+		if v.For != nil {
+			r.write(" ", r.keyword(token.FOR, ""), " ")
+			r.render(v.For)
+		}
+		r.write(")")
+	case *ExtractExpression:
+		r.write(r.keyword("EXTRACT", ""), "(", r.keyword(string(v.Field), ""), " ", r.keyword(token.FROM, ""), " ")
+		r.render(v.Source)
+		r.write(")")
+	case *SubstringSimilarExpression:
+		r.write(r.keyword("SUBSTRING", ""), "(")
+		r.render(v.Source)
+		r.write(" ", r.keyword(token.SIMILAR, ""), " ")
+		r.render(v.Pattern)
+		r.write(" ", r.keyword(token.ESCAPE, ""), " ")
+		r.render(v.Escape)
+		r.write(")")
+	case *OverlayExpression:
+		r.write(r.keyword("OVERLAY", ""), "(")
+		r.render(v.Source)
+		r.write(" ", r.keyword(token.PLACING, ""), " ")
+		r.render(v.Placing)
+		r.write(" ", r.keyword(token.FROM, ""), " ")
+		r.render(v.From)
+		if v.For != nil {
+			r.write(" ", r.keyword(token.FOR, ""), " ")
+			r.render(v.For)
+		}
+		r.write(")")
+	case *CastExpression:
+		r.renderCast(v)
+	case *TypeReference:
+		r.write(v.Name)
+		if v.Args != nil {
+			r.write("(")
+			r.list(v.Args)
+			r.write(")")
+		}
+		r.write(strings.Repeat("[]", v.ArrayDims))
+	case *OrderByItem:
+		r.render(v.Expr)
+		if v.Direction != "" {
+			r.write(" ", r.keyword(string(v.Direction), ""))
+		}
+	default:
+		r.write(expr.String())
+	}
+}
+
+func (r *renderer) renderPrefix(v *PrefixExpression) {
+	closeGroup := r.group()
+	switch v.Token.Type {
+	case token.NOT:
+		r.write(r.keyword(token.NOT, v.Token.Literal), " ")
+		r.operand(v.Right, precNot+1)
+	case token.DISTINCT:
+		r.write(r.keyword(token.DISTINCT, v.Token.Literal), " ")
+		r.operand(v.Right, precPrefix+1)
+	default:
+		r.write(v.Symbol())
+		r.operand(v.Right, precPrefix+1)
+	}
+	closeGroup()
+}
+
+func (r *renderer) renderInfix(v *InfixExpression) {
+	prec := precedence(v)
+
+	closeGroup := r.group()
+	r.operand(v.Left, prec)
+	r.write(" ", r.operator(v.Symbol(), v.Token.Literal), " ")
+	// `x IN (1)` is parsed as `x IN 1`, keep the list parentheses
+	_, isTuple := v.Right.(*TupleExpression)
+	if (v.Operator() == token.IN || v.Operator() == token.NOT_IN) && !isTuple && !v.IsInUnnest() && r.opts.Parentheses == ParenthesesMinimal {
+		r.write("(")
+		r.render(v.Right)
+		r.write(")")
+	} else {
+		r.operand(v.Right, prec+1)
+	}
+	closeGroup()
+}
+
+func (r *renderer) renderBetween(left, rng Expression, op string) {
+	closeGroup := r.group()
+	r.operand(left, precIn)
+	r.write(" ", r.keyword(op, ""), " ")
+
+	bounds, ok := rng.(*InfixExpression)
+	if !ok || r.opts.Parentheses == ParenthesesAlways {
+		// Ranges are kept as the `AND` expression `String()` renders
+		r.render(rng)
+	} else {
+		r.operand(bounds.Left, precCond+1)
+		r.write(" ", r.operator(bounds.Symbol(), bounds.Token.Literal), " ")
+		r.operand(bounds.Right, precCond+1)
+	}
+	closeGroup()
+}
+
+func (r *renderer) renderCast(v *CastExpression) {
+	if v.IsDoubleColon() {
+		closeGroup := r.group()
+		r.operand(v.Expr, precCast)
+		r.write(token.COLON2)
+		if v.Type != nil {
+			r.render(v.Type)
+		}
+		closeGroup()
+		return
+	}
+
+	r.write(r.keyword("CAST", ""), "(")
+	r.render(v.Expr)
+	r.write(" ", r.keyword(token.AS, ""), " ")
+	if v.Type != nil {
+		r.render(v.Type)
+	}
+	if v.Format != nil {
+		r.write(" ", r.keyword("FORMAT", ""), " ")
+		r.render(v.Format)
+	}
+	r.write(")")
+}
+
+func (r *renderer) renderCall(v *CallExpression) {
+	if ident, ok := v.Fn.(*Identifier); ok {
+		r.write(ident.Value)
+	} else if name, ok := v.Fn.(*QualifiedIdentifier); ok {
+		r.write(name.String())
+	} else {
+		r.render(v.Fn)
+	}
+	r.write("(")
+	if v.Distinct {
+		r.write(r.keyword(token.DISTINCT, ""), " ")
+	}
+	r.list(v.Arguments)
+	if v.OrderBy != nil {
+		r.write(" ", r.keyword(token.ORDER, ""), " ", r.keyword(token.BY, ""), " ")
+		for i, item := range v.OrderBy {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.render(item)
+		}
+	}
+	r.write(")")
+
+	if v.WithinGroup != nil {
+		r.write(" ", r.keyword(token.WITHIN, ""), " ", r.keyword(token.GROUP, ""), " (", r.keyword(token.ORDER, ""), " ", r.keyword(token.BY, ""), " ")
+		for i, item := range v.WithinGroup {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.render(item)
+		}
+		r.write(")")
+	}
+}
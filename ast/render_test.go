@@ -0,0 +1,176 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/lexer"
+	"github.com/chenjunwen186/sqlexpr/parser"
+)
+
+func TestRender(t *testing.T) {
+	type TestCase struct {
+		input    string
+		opts     ast.RenderOptions
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"CASE WHEN a THEN 1 END", ast.RenderOptions{KeywordCase: ast.KeywordLower}, "case when a then 1 end"},
+		{"CASE WHEN a THEN 1 END", ast.RenderOptions{IdentifierQuote: ast.QuoteDouble}, `CASE WHEN "a" THEN 1 END`},
+		{"CASE WHEN a THEN 1 END", ast.RenderOptions{KeywordCase: ast.KeywordLower, IdentifierQuote: ast.QuoteDouble}, `case when "a" then 1 end`},
+		{"a and b is not null", ast.RenderOptions{}, "(a AND (b IS NOT NULL))"},
+		{"a and b is not null", ast.RenderOptions{KeywordCase: ast.KeywordPreserve}, "(a and (b IS NOT null))"},
+		{"a + f(b) = c", ast.RenderOptions{IdentifierQuote: ast.QuoteBacktick}, "((`a` + f(`b`)) = `c`)"},
+		{"a = b", ast.RenderOptions{IdentifierQuote: ast.QuoteBracket}, "([a] = [b])"},
+		{"not x between 1 and 2", ast.RenderOptions{KeywordCase: ast.KeywordLower}, "(not (x between (1 and 2)))"},
+		{"case x when 1 then y end", ast.RenderOptions{KeywordCase: ast.KeywordLower, IdentifierQuote: ast.QuoteDouble}, `case "x" when 1 then "y" end`},
+		{"TRIM(LEADING 'x' FROM s)", ast.RenderOptions{KeywordCase: ast.KeywordLower}, "trim(leading 'x' from s)"},
+		{"CAST(x AS INT) COLLATE nocase", ast.RenderOptions{KeywordCase: ast.KeywordLower}, "(cast(x as INT) collate nocase)"},
+		{"f(a, sep => b)", ast.RenderOptions{IdentifierQuote: ast.QuoteDouble}, `f("a", sep => "b")`},
+		{"a + b as c", ast.RenderOptions{KeywordCase: ast.KeywordPreserve}, "(a + b) as c"},
+		{"COUNT(*) + COUNT(t.*)", ast.RenderOptions{IdentifierQuote: ast.QuoteDouble}, `(COUNT(*) + COUNT("t".*))`},
+		{"array[1, 2]", ast.RenderOptions{KeywordCase: ast.KeywordPreserve}, "array[1, 2]"},
+		{"s.t.col = u.f(v.x)", ast.RenderOptions{IdentifierQuote: ast.QuoteDouble}, `("s"."t"."col" = u.f("v"."x"))`},
+		// Keyword operators follow KeywordCase with OriginalOperators, symbols keep their spelling
+		{"NOT a AND b", ast.RenderOptions{KeywordCase: ast.KeywordLower, OriginalOperators: true}, "((not a) and b)"},
+		{"x IS NOT NULL Or y LIKE 'a'", ast.RenderOptions{KeywordCase: ast.KeywordLower, OriginalOperators: true}, "((x is not null) or (y like 'a'))"},
+		{"a and b", ast.RenderOptions{OriginalOperators: true}, "(a AND b)"},
+		{"a Or b", ast.RenderOptions{KeywordCase: ast.KeywordPreserve, OriginalOperators: true}, "(a Or b)"},
+		{"a != b", ast.RenderOptions{KeywordCase: ast.KeywordLower, OriginalOperators: true}, "(a != b)"},
+	}
+	for _, input := range inputs {
+		actual := ast.Render(parseExpression(t, input.input), input.opts)
+		if actual != input.expected {
+			t.Errorf("Render(%q) not %q, got %q", input.input, input.expected, actual)
+		}
+	}
+}
+
+func TestRenderMinimalParentheses(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"a + b * c", "a + b * c"},
+		{"(a + b) * c", "(a + b) * c"},
+		{"a - (b - c)", "a - (b - c)"},
+		{"a - b - c", "a - b - c"},
+		{"a OR b AND c", "a OR b AND c"},
+		{"a OR (b AND c)", "a OR (b AND c)"},
+		{"NOT (a AND b)", "NOT (a AND b)"},
+		{"NOT a = b", "NOT a = b"},
+		{"-(-x)", "-(-x)"},
+		{"-(a + b)", "-(a + b)"},
+		{"a - -b", "a - -b"},
+		{"x BETWEEN 1 AND 2 AND y", "x BETWEEN 1 AND 2 AND y"},
+		{"x BETWEEN (a OR b) AND 2", "x BETWEEN (a OR b) AND 2"},
+		{"x IN (1)", "x IN (1)"},
+		{"x NOT IN (1, 2)", "x NOT IN (1, 2)"},
+		{"x IN UNNEST(tags)", "x IN UNNEST(tags)"},
+		{"(a = b) IS NULL", "(a = b) IS NULL"},
+		{"(a @> b) = c", "a @> b = c"},
+		{"a @> (b = c)", "a @> (b = c)"},
+		{"(a && b) <@ c", "a && b <@ c"},
+		{"a && (b <@ c)", "a && (b <@ c)"},
+		{"data -> (a + 1)", "data -> (a + 1)"},
+		{"(data ->> a) + 1", "data ->> a + 1"},
+		{"(data -> 'a') ->> 'b'", "data -> 'a' ->> 'b'"},
+		{"data -> ('a' -> 'b')", "data -> ('a' -> 'b')"},
+		{"(data -> 'a')[1]", "(data -> 'a')[1]"},
+		{"(data -> 'a')::text", "data -> 'a'::text"},
+		{"(ARRAY[]::int[])", "ARRAY[]::int[]"},
+		{"count(distinct a + 1)", "count(DISTINCT a + 1)"},
+		{"(a + b) AS total", "a + b AS total"},
+		{"(a OR b) as c", "a OR b AS c"},
+		{"CAST(x AS text[][])", "CAST(x AS text[][])"},
+		{"data -> ('a'::text)", "data -> ('a'::text)"},
+		{"(a ? b : c) ? d : e", "(a ? b : c) ? d : e"},
+		{"a ? b : (c ? d : e)", "a ? b : c ? d : e"},
+		{"a ? (b ? c : d) : e", "a ? b ? c : d : e"},
+		{"(x > 1 AND y) ? a + 1 : b", "x > 1 AND y ? a + 1 : b"},
+		{"-(1::int)", "-1::int"},
+		{"(-1)::int", "(-1)::int"},
+		{"(a + b)::text::varchar(10)", "(a + b)::text::varchar(10)"},
+		{"(a + b)[1]", "(a + b)[1]"},
+		{"-(a[1])", "-a[1]"},
+		{"(x::int)[1][2]", "(x::int)[1][2]"},
+		{"a | (b & c)", "a | b & c"},
+		{"(a | b) & c", "(a | b) & c"},
+		{"(1 << 2) + 3", "(1 << 2) + 3"},
+		{"x & (1 << n) = 0", "x & 1 << n = 0"},
+		{"f((a + b) * 2, CASE WHEN x THEN y END)", "f((a + b) * 2, CASE WHEN x THEN y END)"},
+		{"(a + b) COLLATE c", "(a + b) COLLATE c"},
+		{"(a AND b) IS NULL", "(a AND b) IS NULL"},
+		{"POSITION((a IN b) IN c)", "POSITION((a IN (b)) IN c)"},
+	}
+	opts := ast.RenderOptions{Parentheses: ast.ParenthesesMinimal}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		actual := ast.Render(expr, opts)
+		if actual != input.expected {
+			t.Errorf("Render(%q) not %q, got %q", input.input, input.expected, actual)
+		}
+
+		reparsed := parseExpression(t, actual)
+		if d := ast.Diff(expr, reparsed); d != "" {
+			t.Errorf("Render(%q) changed the tree: %s", input.input, d)
+		}
+	}
+}
+
+func TestRenderJSONPathOperators(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"(data #> '{a}') = 'x'", "data #> '{a}' = 'x'"},
+		{"data #> (a + 1)", "data #> a + 1"},
+		{"(data #>> '{a}') + 1", "(data #>> '{a}') + 1"},
+	}
+	opts := ast.RenderOptions{Parentheses: ast.ParenthesesMinimal}
+	parse := func(input string) ast.Expression {
+		expr, err := parser.New(lexer.NewWithOptions(input, lexer.Options{JSONPathOperators: true})).ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input, err)
+		}
+		return expr
+	}
+	for _, input := range inputs {
+		expr := parse(input.input)
+		actual := ast.Render(expr, opts)
+		if actual != input.expected {
+			t.Errorf("Render(%q) not %q, got %q", input.input, input.expected, actual)
+		}
+
+		if d := ast.Diff(expr, parse(actual)); d != "" {
+			t.Errorf("Render(%q) changed the tree: %s", input.input, d)
+		}
+	}
+}
+
+func TestRenderLikeString(t *testing.T) {
+	inputs := []string{
+		"a + b * c - -d",
+		"NOT a IN (1, 2) OR b NOT LIKE ANY ('x%', 'y%')",
+		"x NOT BETWEEN f(1) AND 2 AND y IS NOT NULL",
+		"SUBSTRING(s FROM 1 FOR 2) COLLATE c",
+		"OVERLAY(s PLACING 'X' FROM 2 FOR 1)",
+		"SUBSTRING(s SIMILAR '%#\"o_b#\"%' ESCAPE '#')",
+		"has([1, [2, 3], []], x)",
+		"CASE WHEN a THEN TRUE ELSE NULL END",
+		"DATE_SUB(d, INTERVAL (n + 1) DAY)",
+		"EXTRACT(YEAR FROM '2023-05-15 14:30:00') = 2023",
+		"name NOT LIKE '100!%' ESCAPE '!' AND a LIKE (b + c) ESCAPE e",
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input)
+		if actual := ast.Render(expr, ast.RenderOptions{}); actual != expr.String() {
+			t.Errorf("Render(%q) not %q, got %q", input, expr.String(), actual)
+		}
+	}
+}
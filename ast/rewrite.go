@@ -0,0 +1,321 @@
+package ast
+
+// Rewrite returns a copy of the expression tree where every node,
+// from the leaves up to the root, is replaced with the result of fn.
+// fn receives a copy whose children are already rewritten,
+// so the input tree is never modified.
+func Rewrite(expr Expression, fn func(Expression) Expression) Expression {
+	if expr == nil {
+		return nil
+	}
+
+	return fn(copyWithChildren(expr, func(child Expression) Expression {
+		return Rewrite(child, fn)
+	}))
+}
+
+// Clone returns a deep copy of the expression tree
+func Clone(expr Expression) Expression {
+	return Rewrite(expr, func(node Expression) Expression {
+		return node
+	})
+}
+
+// Replace returns a copy of the expression tree where the node that is
+// pointer-identical to target is replaced with replacement,
+// and whether it was found. The input tree is never modified,
+// replacement is used as is without being copied.
+func Replace(root, target, replacement Expression) (Expression, bool) {
+	var (
+		replaced bool
+		replace  func(Expression) Expression
+	)
+	replace = func(expr Expression) Expression {
+		if expr == target {
+			replaced = true
+			return replacement
+		}
+		return copyWithChildren(expr, replace)
+	}
+
+	if root == nil {
+		return nil, false
+	}
+
+	return replace(root), replaced
+}
+
+// Returns a shallow copy of the node with each child replaced by rewrite(child)
+func copyWithChildren(expr Expression, rewrite func(Expression) Expression) Expression {
+	optional := func(child Expression) Expression {
+		if child == nil {
+			return nil
+		}
+		return rewrite(child)
+	}
+	list := func(children []Expression) []Expression {
+		if children == nil {
+			return nil
+		}
+		result := make([]Expression, len(children))
+		for i, child := range children {
+			result[i] = rewrite(child)
+		}
+		return result
+	}
+	orderBy := func(items []*OrderByItem) []*OrderByItem {
+		if items == nil {
+			return nil
+		}
+		result := make([]*OrderByItem, len(items))
+		for i, item := range items {
+			result[i] = item
+			if item, ok := rewrite(item).(*OrderByItem); ok {
+				result[i] = item
+			}
+		}
+		return result
+	}
+
+	switch v := expr.(type) {
+	case *Identifier:
+		c := *v
+		return &c
+	case *NullLiteral:
+		c := *v
+		return &c
+	case *DefaultLiteral:
+		c := *v
+		return &c
+	case *BooleanLiteral:
+		c := *v
+		return &c
+	case *StringLiteral:
+		c := *v
+		return &c
+	case *NumberLiteral:
+		c := *v
+		return &c
+	case *QualifiedIdentifier:
+		c := *v
+		c.Parts = make([]*Identifier, len(v.Parts))
+		for i, part := range v.Parts {
+			c.Parts[i] = part
+			if ident, ok := rewrite(part).(*Identifier); ok {
+				c.Parts[i] = ident
+			}
+		}
+		return &c
+	case *Wildcard:
+		c := *v
+		return &c
+	case *QualifiedWildcard:
+		c := *v
+		if ident, ok := rewrite(v.Table).(*Identifier); ok {
+			c.Table = ident
+		}
+		return &c
+	case *PrefixExpression:
+		c := *v
+		c.Right = rewrite(v.Right)
+		return &c
+	case *InfixExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Right = rewrite(v.Right)
+		return &c
+	case *CustomOperatorExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Right = rewrite(v.Right)
+		return &c
+	case *CallExpression:
+		c := *v
+		c.Fn = rewrite(v.Fn)
+		c.Arguments = list(v.Arguments)
+		c.OrderBy = orderBy(v.OrderBy)
+		c.WithinGroup = orderBy(v.WithinGroup)
+		return &c
+	case *OrderByItem:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		return &c
+	case *NamedArgument:
+		c := *v
+		if v.Name != nil {
+			if name, ok := rewrite(v.Name).(*Identifier); ok {
+				c.Name = name
+			}
+		}
+		c.Value = rewrite(v.Value)
+		return &c
+	case *GroupingConstruct:
+		c := *v
+		c.Args = list(v.Args)
+		return &c
+	case *CaseWhenExpression:
+		c := *v
+		c.Operand = optional(v.Operand)
+		c.Whens = make([]When, len(v.Whens))
+		for i, when := range v.Whens {
+			c.Whens[i] = When{Cond: rewrite(when.Cond), Then: rewrite(when.Then), Comment: when.Comment}
+		}
+		c.Else = optional(v.Else)
+		return &c
+	case *BetweenExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Range = rewrite(v.Range)
+		return &c
+	case *NotBetweenExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Range = rewrite(v.Range)
+		return &c
+	case *TupleExpression:
+		c := *v
+		c.Expressions = list(v.Expressions)
+		return &c
+	case *ArrayLiteral:
+		c := *v
+		c.Elements = list(v.Elements)
+		return &c
+	case *IntervalExpression:
+		c := *v
+		c.Value = rewrite(v.Value)
+		return &c
+	case *LambdaExpression:
+		c := *v
+		c.Params = make([]*Identifier, len(v.Params))
+		for i, param := range v.Params {
+			c.Params[i] = param
+			if param, ok := rewrite(param).(*Identifier); ok {
+				c.Params[i] = param
+			}
+		}
+		c.Body = rewrite(v.Body)
+		return &c
+	case *IndexExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Index = rewrite(v.Index)
+		return &c
+	case *AliasExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		if ident, ok := rewrite(v.Alias).(*Identifier); ok {
+			c.Alias = ident
+		}
+		return &c
+	case *TernaryExpression:
+		c := *v
+		c.Cond = rewrite(v.Cond)
+		c.True = rewrite(v.True)
+		c.False = rewrite(v.False)
+		return &c
+	case *JSONAccessExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Index = rewrite(v.Index)
+		return &c
+	case *IsExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		return &c
+	case *IsNormalizedExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		return &c
+	case *IsDocumentExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		return &c
+	case *IsASetExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		return &c
+	case *TypeReference:
+		c := *v
+		c.Args = list(v.Args)
+		return &c
+	case *IsOfExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		c.Types = make([]*TypeReference, len(v.Types))
+		for i, typ := range v.Types {
+			c.Types[i] = typ
+			if typ, ok := rewrite(typ).(*TypeReference); ok {
+				c.Types[i] = typ
+			}
+		}
+		return &c
+	case *CastExpression:
+		c := *v
+		c.Expr = rewrite(v.Expr)
+		if v.Type != nil {
+			if typ, ok := rewrite(v.Type).(*TypeReference); ok {
+				c.Type = typ
+			}
+		}
+		c.Format = optional(v.Format)
+		return &c
+	case *CollateExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		if v.Collation != nil {
+			if ident, ok := rewrite(v.Collation).(*Identifier); ok {
+				c.Collation = ident
+			}
+		}
+		return &c
+	case *UnnestExpression:
+		c := *v
+		c.Array = rewrite(v.Array)
+		return &c
+	case *PositionExpression:
+		c := *v
+		c.Substr = rewrite(v.Substr)
+		c.Str = rewrite(v.Str)
+		return &c
+	case *TrimExpression:
+		c := *v
+		c.Chars = optional(v.Chars)
+		c.Source = rewrite(v.Source)
+		return &c
+	case *SubstringExpression:
+		c := *v
+		c.Source = rewrite(v.Source)
+		c.From = optional(v.From)
+		c.For = optional(v.For)
+		return &c
+	case *ExtractExpression:
+		c := *v
+		c.Source = rewrite(v.Source)
+		return &c
+	case *SubstringSimilarExpression:
+		c := *v
+		c.Source = rewrite(v.Source)
+		c.Pattern = rewrite(v.Pattern)
+		c.Escape = rewrite(v.Escape)
+		return &c
+	case *OverlayExpression:
+		c := *v
+		c.Source = rewrite(v.Source)
+		c.Placing = rewrite(v.Placing)
+		c.From = rewrite(v.From)
+		c.For = optional(v.For)
+		return &c
+	case *EscapeExpression:
+		c := *v
+		c.Pattern = rewrite(v.Pattern)
+		c.Escape = rewrite(v.Escape)
+		return &c
+	case *LikeExpression:
+		c := *v
+		c.Left = rewrite(v.Left)
+		c.Patterns = list(v.Patterns)
+		return &c
+	}
+
+	return expr
+}
@@ -0,0 +1,75 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestRewrite(t *testing.T) {
+	input := "f(a, b) + CASE WHEN a > 1 THEN a ELSE c END"
+	expr := parseExpression(t, input)
+
+	rewritten := ast.Rewrite(expr, func(node ast.Expression) ast.Expression {
+		if v, ok := node.(*ast.Identifier); ok && v.Value == "a" {
+			return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"}
+		}
+		return node
+	})
+
+	expected := "(f(x, b) + CASE WHEN (x > 1) THEN x ELSE c END)"
+	if rewritten.String() != expected {
+		t.Errorf("rewritten.String() not %q, got %q", expected, rewritten.String())
+	}
+
+	original := "(f(a, b) + CASE WHEN (a > 1) THEN a ELSE c END)"
+	if expr.String() != original {
+		t.Errorf("expr.String() not %q, got %q", original, expr.String())
+	}
+}
+
+func TestReplace(t *testing.T) {
+	expr := parseExpression(t, "f(a, a + 1) = a")
+	call := expr.(*ast.InfixExpression).Left.(*ast.CallExpression)
+	target := call.Arguments[0]
+	replacement := parseExpression(t, "g(b)")
+
+	replaced, ok := ast.Replace(expr, target, replacement)
+	if !ok {
+		t.Fatalf("Replace() did not find the target")
+	}
+	expected := "(f(g(b), (a + 1)) = a)"
+	if replaced.String() != expected {
+		t.Errorf("replaced.String() not %q, got %q", expected, replaced.String())
+	}
+	if expr.String() != "(f(a, (a + 1)) = a)" {
+		t.Errorf("expr is modified, got %q", expr.String())
+	}
+	if replaced.(*ast.InfixExpression).Left.(*ast.CallExpression).Arguments[0] != replacement {
+		t.Errorf("replacement is not used as is")
+	}
+
+	// An equal but different node is not replaced
+	other := parseExpression(t, "a")
+	if replaced, ok := ast.Replace(expr, other, replacement); ok || replaced.String() != expr.String() {
+		t.Errorf("Replace() with a foreign target should not replace, got %t %q", ok, replaced.String())
+	}
+
+	if replaced, ok := ast.Replace(expr, expr, replacement); !ok || replaced != replacement {
+		t.Errorf("Replace() of the root should return the replacement, got %t %q", ok, replaced.String())
+	}
+}
+
+func TestClone(t *testing.T) {
+	expr := parseExpression(t, "a + f(b) * -c")
+	clone := ast.Clone(expr)
+	if clone.String() != expr.String() {
+		t.Errorf("clone.String() not %q, got %q", expr.String(), clone.String())
+	}
+
+	clone.(*ast.InfixExpression).Left.(*ast.Identifier).Value = "x"
+	if expr.String() != "(a + (f(b) * (-c)))" {
+		t.Errorf("expr is modified by its clone, got %q", expr.String())
+	}
+}
@@ -0,0 +1,50 @@
+package ast
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// Simplify returns a simplified copy of the expression without changing its meaning:
+//
+//   - `NOT NOT x` and `- - x` become `x`
+//   - `x AND TRUE` and `TRUE AND x` become `x`
+//   - `x OR FALSE` and `FALSE OR x` become `x`
+//
+// Grouping parentheses are not kept in the tree, so there are none to collapse.
+func Simplify(expr Expression) Expression {
+	return Rewrite(expr, simplifyNode)
+}
+
+func simplifyNode(expr Expression) Expression {
+	switch v := expr.(type) {
+	case *PrefixExpression:
+		switch v.Token.Type {
+		case token.NOT, token.MINUS:
+			if inner, ok := v.Right.(*PrefixExpression); ok && inner.Token.Type == v.Token.Type {
+				return inner.Right
+			}
+		}
+	case *InfixExpression:
+		switch v.Operator() {
+		case token.AND:
+			if isBooleanLiteral(v.Right, true) {
+				return v.Left
+			}
+			if isBooleanLiteral(v.Left, true) {
+				return v.Right
+			}
+		case token.OR:
+			if isBooleanLiteral(v.Right, false) {
+				return v.Left
+			}
+			if isBooleanLiteral(v.Left, false) {
+				return v.Right
+			}
+		}
+	}
+
+	return expr
+}
+
+func isBooleanLiteral(expr Expression, value bool) bool {
+	v, ok := expr.(*BooleanLiteral)
+	return ok && v.Value() == value
+}
@@ -0,0 +1,45 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+)
+
+func TestSimplify(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{"NOT NOT x", "x"},
+		{"NOT NOT NOT x", "(NOT x)"},
+		{"- - x", "x"},
+		{"-(-(x + 1))", "(x + 1)"},
+		{"x AND TRUE", "x"},
+		{"TRUE AND x", "x"},
+		{"x OR FALSE", "x"},
+		{"FALSE OR x", "x"},
+		{"((a = 1)) AND TRUE", "(a = 1)"},
+		{"f(NOT NOT a, - - b) OR FALSE", "f(a, b)"},
+		{"NOT NOT (a AND TRUE)", "a"},
+		// Not applicable
+		{"x AND FALSE", "(x AND FALSE)"},
+		{"x OR TRUE", "(x OR TRUE)"},
+		{"-(+x)", "(-(+x))"},
+		{"NOT (-x)", "(NOT (-x))"},
+		{"a + b * c", "(a + (b * c))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		before := expr.String()
+		actual := ast.Simplify(expr)
+		if actual.String() != input.expected {
+			t.Errorf("Simplify(%q) not %q, got %q", input.input, input.expected, actual.String())
+		}
+		if expr.String() != before {
+			t.Errorf("Simplify(%q) modified the input, got %q", input.input, expr.String())
+		}
+	}
+}
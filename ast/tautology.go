@@ -0,0 +1,121 @@
+package ast
+
+import (
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+// AlwaysTrue reports whether the predicate is trivially true regardless of its inputs,
+// like `TRUE`, `1 = 1` or `x OR TRUE`.
+// Only constant comparisons of number literals and boolean connectives are recognized,
+// anything else is not known to be always true.
+func AlwaysTrue(expr Expression) bool {
+	value, ok := constantPredicate(expr)
+	return ok && value
+}
+
+// AlwaysFalse reports whether the predicate is trivially false regardless of its inputs,
+// like `FALSE`, `1 = 0` or `x AND FALSE`, see `AlwaysTrue`
+func AlwaysFalse(expr Expression) bool {
+	value, ok := constantPredicate(expr)
+	return ok && !value
+}
+
+// constantPredicate returns the value of the predicate if it is known without its inputs
+func constantPredicate(expr Expression) (bool, bool) {
+	switch v := expr.(type) {
+	case *BooleanLiteral:
+		return v.Value(), true
+	case *PrefixExpression:
+		if v.Token.Type != token.NOT {
+			return false, false
+		}
+		value, ok := constantPredicate(v.Right)
+		return !value, ok
+	case *InfixExpression:
+		switch v.Operator() {
+		case token.AND:
+			left, leftOk := constantPredicate(v.Left)
+			right, rightOk := constantPredicate(v.Right)
+			if (leftOk && !left) || (rightOk && !right) {
+				return false, true
+			}
+			return true, leftOk && rightOk
+		case token.OR:
+			left, leftOk := constantPredicate(v.Left)
+			right, rightOk := constantPredicate(v.Right)
+			if (leftOk && left) || (rightOk && right) {
+				return true, true
+			}
+			return false, leftOk && rightOk
+		default:
+			return compareNumbers(v)
+		}
+	default:
+		return false, false
+	}
+}
+
+func compareNumbers(expr *InfixExpression) (bool, bool) {
+	cmp, ok := compareNumberLiterals(expr.Left, expr.Right)
+	if !ok {
+		return false, false
+	}
+
+	switch expr.Operator() {
+	case token.EQ:
+		return cmp == 0, true
+	case token.BANG_EQ, token.NOT_EQ:
+		return cmp != 0, true
+	case token.LT:
+		return cmp < 0, true
+	case token.LT_EQ:
+		return cmp <= 0, true
+	case token.GT:
+		return cmp > 0, true
+	case token.GT_EQ:
+		return cmp >= 0, true
+	default:
+		return false, false
+	}
+}
+
+// Compares number literals with the values of `NumberLiteral.IntValue` and `NumberLiteral.Value`
+// like the evaluator, so `010 = 8` is true and integers beyond 2^53 are not rounded
+func compareNumberLiterals(left, right Expression) (int, bool) {
+	l, ok := left.(*NumberLiteral)
+	if !ok || l.IsSpecialFloat() {
+		return 0, false
+	}
+	r, ok := right.(*NumberLiteral)
+	if !ok || r.IsSpecialFloat() {
+		return 0, false
+	}
+
+	if li, ok := l.IntValue(); ok {
+		if ri, ok := r.IntValue(); ok {
+			return compareOrdered(li, ri), true
+		}
+	}
+
+	lf, err := l.Value()
+	if err != nil {
+		return 0, false
+	}
+	rf, err := r.Value()
+	if err != nil {
+		return 0, false
+	}
+
+	return compareOrdered(lf, rf), true
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
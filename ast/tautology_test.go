@@ -0,0 +1,67 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+)
+
+func TestAlwaysTrueAndAlwaysFalse(t *testing.T) {
+	type TestCase struct {
+		input       string
+		alwaysTrue  bool
+		alwaysFalse bool
+	}
+
+	inputs := []TestCase{
+		{"TRUE", true, false},
+		{"true", true, false},
+		{"FALSE", false, true},
+		{"1 = 1", true, false},
+		{"1 = 1.0", true, false},
+		{"1 = 0", false, true},
+		{"1 <> 0", true, false},
+		{"1 != 1", false, true},
+		{"2 > 1", true, false},
+		{"2 >= 3", false, true},
+		{"1 < 2", true, false},
+		{"2 <= 1", false, true},
+		{"x OR TRUE", true, false},
+		{"TRUE OR x", true, false},
+		{"x AND FALSE", false, true},
+		{"FALSE AND x", false, true},
+		{"x OR 1 = 1", true, false},
+		{"x AND 1 = 0", false, true},
+		{"NOT FALSE", true, false},
+		{"NOT (1 = 1)", false, true},
+		{"TRUE AND 1 = 1", true, false},
+		{"FALSE OR 1 = 0", false, true},
+		{"(x AND FALSE) OR TRUE", true, false},
+		{"010 = 8", true, false},
+		{"010 = 10", false, true},
+		{"0xFF = 255", true, false},
+		{"0b11 < 4.5", true, false},
+		{"9007199254740993 = 9007199254740992", false, true},
+		// Not trivial
+		{"x", false, false},
+		{"x = 1", false, false},
+		{"x = x", false, false},
+		{"x AND TRUE", false, false},
+		{"x OR FALSE", false, false},
+		{"NOT x", false, false},
+		{"'a' = 'a'", false, false},
+		{"1 + 1", false, false},
+		{"NULL", false, false},
+		{"1e400 = 1e400", false, false},
+		{"f(TRUE)", false, false},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if actual := ast.AlwaysTrue(expr); actual != input.alwaysTrue {
+			t.Errorf("AlwaysTrue(%q) not %t, got %t", input.input, input.alwaysTrue, actual)
+		}
+		if actual := ast.AlwaysFalse(expr); actual != input.alwaysFalse {
+			t.Errorf("AlwaysFalse(%q) not %t, got %t", input.input, input.alwaysFalse, actual)
+		}
+	}
+}
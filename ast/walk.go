@@ -0,0 +1,218 @@
+package ast
+
+// Walk traverses the expression tree in pre-order, calling visit with each node.
+// Children of a node are skipped when visit returns false.
+func Walk(expr Expression, visit func(Expression) bool) {
+	WalkContext(expr, func(node, _ Expression, _ string, _ int) bool {
+		return visit(node)
+	})
+}
+
+// Visitor is the interface form of the visit function of Walk
+type Visitor interface {
+	// Visit is called with each node, its children are skipped when it returns false
+	Visit(expr Expression) bool
+}
+
+// WalkVisitor traverses the expression tree in pre-order like Walk, calling v.Visit with each node.
+func WalkVisitor(expr Expression, v Visitor) {
+	Walk(expr, v.Visit)
+}
+
+// FindAll returns every node of the expression tree, including the root,
+// for which match returns true, in pre-order
+func FindAll(expr Expression, match func(Expression) bool) []Expression {
+	var found []Expression
+	Walk(expr, func(node Expression) bool {
+		if match(node) {
+			found = append(found, node)
+		}
+		return true
+	})
+
+	return found
+}
+
+// WalkContext traverses the expression tree in pre-order,
+// calling fn with each node, its parent, the parent's field holding it
+// and the position in that field when it is a list (otherwise -1).
+// The root is visited with a nil parent, an empty field and index -1.
+// Children of a node are skipped when fn returns false.
+//
+// Fields are named after the struct fields of the parent node,
+// `CaseWhenExpression` reports its branches as "Cond" and "Then" indexed by branch,
+// after the "Operand" of the simple form.
+func WalkContext(expr Expression, fn func(node, parent Expression, field string, index int) bool) {
+	if expr == nil {
+		return
+	}
+
+	walkContext(expr, nil, "", -1, fn)
+}
+
+func walkContext(node, parent Expression, field string, index int, fn func(node, parent Expression, field string, index int) bool) {
+	if !fn(node, parent, field, index) {
+		return
+	}
+
+	for _, c := range children(node) {
+		walkContext(c.node, node, c.field, c.index, fn)
+	}
+}
+
+type child struct {
+	node  Expression
+	field string
+	index int
+}
+
+// Lists the non-nil direct children of a node in source order
+func children(expr Expression) []child {
+	var list []child
+	add := func(node Expression, field string, index int) {
+		if node != nil {
+			list = append(list, child{node: node, field: field, index: index})
+		}
+	}
+
+	switch v := expr.(type) {
+	case *QualifiedIdentifier:
+		for i, part := range v.Parts {
+			add(part, "Parts", i)
+		}
+	case *QualifiedWildcard:
+		add(v.Table, "Table", -1)
+	case *PrefixExpression:
+		add(v.Right, "Right", -1)
+	case *InfixExpression:
+		add(v.Left, "Left", -1)
+		add(v.Right, "Right", -1)
+	case *CustomOperatorExpression:
+		add(v.Left, "Left", -1)
+		add(v.Right, "Right", -1)
+	case *CallExpression:
+		add(v.Fn, "Fn", -1)
+		for i, arg := range v.Arguments {
+			add(arg, "Arguments", i)
+		}
+		for i, item := range v.OrderBy {
+			add(item, "OrderBy", i)
+		}
+		for i, item := range v.WithinGroup {
+			add(item, "WithinGroup", i)
+		}
+	case *OrderByItem:
+		add(v.Expr, "Expr", -1)
+	case *NamedArgument:
+		if v.Name != nil {
+			add(v.Name, "Name", -1)
+		}
+		add(v.Value, "Value", -1)
+	case *GroupingConstruct:
+		for i, arg := range v.Args {
+			add(arg, "Args", i)
+		}
+	case *CaseWhenExpression:
+		add(v.Operand, "Operand", -1)
+		for i, when := range v.Whens {
+			add(when.Cond, "Cond", i)
+			add(when.Then, "Then", i)
+		}
+		add(v.Else, "Else", -1)
+	case *BetweenExpression:
+		add(v.Left, "Left", -1)
+		add(v.Range, "Range", -1)
+	case *NotBetweenExpression:
+		add(v.Left, "Left", -1)
+		add(v.Range, "Range", -1)
+	case *TupleExpression:
+		for i, e := range v.Expressions {
+			add(e, "Expressions", i)
+		}
+	case *ArrayLiteral:
+		for i, e := range v.Elements {
+			add(e, "Elements", i)
+		}
+	case *IntervalExpression:
+		add(v.Value, "Value", -1)
+	case *LambdaExpression:
+		for i, param := range v.Params {
+			add(param, "Params", i)
+		}
+		add(v.Body, "Body", -1)
+	case *IndexExpression:
+		add(v.Left, "Left", -1)
+		add(v.Index, "Index", -1)
+	case *AliasExpression:
+		add(v.Expr, "Expr", -1)
+		add(v.Alias, "Alias", -1)
+	case *TernaryExpression:
+		add(v.Cond, "Cond", -1)
+		add(v.True, "True", -1)
+		add(v.False, "False", -1)
+	case *JSONAccessExpression:
+		add(v.Left, "Left", -1)
+		add(v.Index, "Index", -1)
+	case *EscapeExpression:
+		add(v.Pattern, "Pattern", -1)
+		add(v.Escape, "Escape", -1)
+	case *LikeExpression:
+		add(v.Left, "Left", -1)
+		for i, pattern := range v.Patterns {
+			add(pattern, "Patterns", i)
+		}
+	case *IsExpression:
+		add(v.Left, "Left", -1)
+	case *IsNormalizedExpression:
+		add(v.Expr, "Expr", -1)
+	case *IsDocumentExpression:
+		add(v.Expr, "Expr", -1)
+	case *IsASetExpression:
+		add(v.Expr, "Expr", -1)
+	case *TypeReference:
+		for i, arg := range v.Args {
+			add(arg, "Args", i)
+		}
+	case *IsOfExpression:
+		add(v.Expr, "Expr", -1)
+		for i, typ := range v.Types {
+			add(typ, "Types", i)
+		}
+	case *CastExpression:
+		add(v.Expr, "Expr", -1)
+		if v.Type != nil {
+			add(v.Type, "Type", -1)
+		}
+		add(v.Format, "Format", -1)
+	case *CollateExpression:
+		add(v.Left, "Left", -1)
+		if v.Collation != nil {
+			add(v.Collation, "Collation", -1)
+		}
+	case *UnnestExpression:
+		add(v.Array, "Array", -1)
+	case *PositionExpression:
+		add(v.Substr, "Substr", -1)
+		add(v.Str, "Str", -1)
+	case *TrimExpression:
+		add(v.Chars, "Chars", -1)
+		add(v.Source, "Source", -1)
+	case *SubstringExpression:
+		add(v.Source, "Source", -1)
+		add(v.From, "From", -1)
+		add(v.For, "For", -1)
+	case *ExtractExpression:
+		add(v.Source, "Source", -1)
+	case *SubstringSimilarExpression:
+		add(v.Source, "Source", -1)
+		add(v.Pattern, "Pattern", -1)
+		add(v.Escape, "Escape", -1)
+	case *OverlayExpression:
+		add(v.Source, "Source", -1)
+		add(v.Placing, "Placing", -1)
+		add(v.From, "From", -1)
+		add(v.For, "For", -1)
+	}
+
+	return list
+}
@@ -0,0 +1,169 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+)
+
+func TestWalkContext(t *testing.T) {
+	expr := parseExpression(t, "f(a, CASE WHEN x > 1 THEN 1 WHEN x < 0 THEN -1 ELSE y + 2 END)")
+
+	var elseExpr ast.Expression
+	var visited []string
+	ast.WalkContext(expr, func(node, parent ast.Expression, field string, index int) bool {
+		if _, ok := parent.(*ast.CaseWhenExpression); ok && field == "Else" {
+			elseExpr = node
+		}
+
+		var parentStr string
+		if parent != nil {
+			parentStr = parent.String()
+		}
+		visited = append(visited, fmt.Sprintf("%s|%s|%d|%s", node.String(), field, index, parentStr))
+		return true
+	})
+
+	if elseExpr == nil {
+		t.Fatalf("ELSE branch not found")
+	}
+	if elseExpr.String() != "(y + 2)" {
+		t.Errorf("elseExpr.String() not %q, got %q", "(y + 2)", elseExpr.String())
+	}
+
+	caseStr := "CASE WHEN (x > 1) THEN 1 WHEN (x < 0) THEN (-1) ELSE (y + 2) END"
+	expected := []string{
+		"f(a, " + caseStr + ")||-1|",
+		"f|Fn|-1|f(a, " + caseStr + ")",
+		"a|Arguments|0|f(a, " + caseStr + ")",
+		caseStr + "|Arguments|1|f(a, " + caseStr + ")",
+		"(x > 1)|Cond|0|" + caseStr,
+		"x|Left|-1|(x > 1)",
+		"1|Right|-1|(x > 1)",
+		"1|Then|0|" + caseStr,
+		"(x < 0)|Cond|1|" + caseStr,
+		"x|Left|-1|(x < 0)",
+		"0|Right|-1|(x < 0)",
+		"(-1)|Then|1|" + caseStr,
+		"1|Right|-1|(-1)",
+		"(y + 2)|Else|-1|" + caseStr,
+		"y|Left|-1|(y + 2)",
+		"2|Right|-1|(y + 2)",
+	}
+	if len(visited) != len(expected) {
+		t.Fatalf("len(visited) not %d, got %d: %q", len(expected), len(visited), visited)
+	}
+	for i, v := range expected {
+		if visited[i] != v {
+			t.Errorf("visited[%d] not %q, got %q", i, v, visited[i])
+		}
+	}
+}
+
+func TestWalkContextSkipChildren(t *testing.T) {
+	expr := parseExpression(t, "a + f(b, c) * d")
+
+	var visited []string
+	ast.WalkContext(expr, func(node, parent ast.Expression, field string, index int) bool {
+		visited = append(visited, node.String())
+		_, isCall := node.(*ast.CallExpression)
+		return !isCall
+	})
+
+	expected := []string{"(a + (f(b, c) * d))", "a", "(f(b, c) * d)", "f(b, c)", "d"}
+	if fmt.Sprint(visited) != fmt.Sprint(expected) {
+		t.Errorf("visited not %q, got %q", expected, visited)
+	}
+}
+
+func TestWalkVisitsOnce(t *testing.T) {
+	input := "NOT f(a, -b) AND (x, y) IN ((1, 2)) OR CASE WHEN c BETWEEN 1 AND 2 THEN d ELSE e END = 1"
+	expr := parseExpression(t, input)
+
+	seen := map[ast.Expression]int{}
+	ast.Walk(expr, func(node ast.Expression) bool {
+		seen[node]++
+		return true
+	})
+
+	for node, n := range seen {
+		if n != 1 {
+			t.Errorf("%q visited %d times", node.String(), n)
+		}
+	}
+
+	expected := []string{
+		"f(a, (-b))", "f", "a", "(-b)", "b",
+		"(x, y)", "x", "y", "(1, 2)", "1", "2",
+		"(c BETWEEN (1 AND 2))", "c", "d", "e",
+	}
+	for _, v := range expected {
+		if len(ast.FindAll(expr, func(node ast.Expression) bool { return node.String() == v })) == 0 {
+			t.Errorf("%q not visited", v)
+		}
+	}
+}
+
+type countVisitor struct {
+	idents []string
+	skip   bool
+}
+
+func (c *countVisitor) Visit(expr ast.Expression) bool {
+	if ident, ok := expr.(*ast.Identifier); ok {
+		c.idents = append(c.idents, ident.Value)
+	}
+	_, isCall := expr.(*ast.CallExpression)
+	return !(c.skip && isCall)
+}
+
+func TestWalkVisitor(t *testing.T) {
+	expr := parseExpression(t, "a + f(b, c) * d")
+
+	v := &countVisitor{}
+	ast.WalkVisitor(expr, v)
+	if fmt.Sprint(v.idents) != "[a f b c d]" {
+		t.Errorf("v.idents not %q, got %q", "[a f b c d]", v.idents)
+	}
+
+	v = &countVisitor{skip: true}
+	ast.WalkVisitor(expr, v)
+	if fmt.Sprint(v.idents) != "[a d]" {
+		t.Errorf("v.idents not %q, got %q", "[a d]", v.idents)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	expr := parseExpression(t, "f(g(x)) + h(y)")
+	calls := ast.FindAll(expr, func(node ast.Expression) bool {
+		_, ok := node.(*ast.CallExpression)
+		return ok
+	})
+
+	expected := []string{"f(g(x))", "g(x)", "h(y)"}
+	if len(calls) != len(expected) {
+		t.Fatalf("len(calls) not %d, got %d", len(expected), len(calls))
+	}
+	for i, v := range expected {
+		if calls[i].String() != v {
+			t.Errorf("calls[%d].String() not %q, got %q", i, v, calls[i].String())
+		}
+	}
+
+	root := ast.FindAll(expr, func(node ast.Expression) bool {
+		_, ok := node.(*ast.InfixExpression)
+		return ok
+	})
+	if len(root) != 1 || root[0] != expr {
+		t.Errorf("FindAll() should include the root, got %v", root)
+	}
+
+	none := ast.FindAll(expr, func(node ast.Expression) bool {
+		_, ok := node.(*ast.StringLiteral)
+		return ok
+	})
+	if len(none) != 0 {
+		t.Errorf("len(none) not 0, got %d", len(none))
+	}
+}
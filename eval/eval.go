@@ -0,0 +1,385 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chenjunwen186/sqlexpr/ast"
+	"github.com/chenjunwen186/sqlexpr/lexer"
+	"github.com/chenjunwen186/sqlexpr/parser"
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+// Env maps identifiers to their values.
+// Values are `nil` for NULL, `bool`, `string` or any Go integer or float type,
+// integers are evaluated as `int64` and floats as `float64`.
+type Env map[string]any
+
+// Program is a parsed expression that can be evaluated many times
+type Program struct {
+	expr ast.Expression
+}
+
+// Compile parses the expression once for evaluating it with different environments.
+// The whole input must be a single expression, like `a * b + 1`.
+func Compile(input string) (*Program, error) {
+	p := parser.New(lexer.New(input))
+	expr, err := p.ParseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil {
+		return nil, fmt.Errorf("empty expression")
+	}
+	if rest := p.RemainingTokens(); len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected %s after the expression", rest[0].Literal)
+	}
+
+	return &Program{expr: expr}, nil
+}
+
+// Expression returns the parsed expression of the program
+func (p *Program) Expression() ast.Expression {
+	return p.expr
+}
+
+// Eval evaluates the program with the values of the identifiers in env.
+// The result is `nil` for NULL, `bool`, `string`, `int64` or `float64`.
+//
+// NULL propagates through arithmetic and comparisons, AND and OR follow
+// the three-valued logic of SQL. Division of two integers truncates like Postgres,
+// and integer results beyond the int64 range are errors instead of wrapping around.
+func (p *Program) Eval(env Env) (any, error) {
+	return eval(p.expr, env)
+}
+
+func eval(expr ast.Expression, env Env) (any, error) {
+	switch v := expr.(type) {
+	case *ast.NumberLiteral:
+		return numberValue(v)
+	case *ast.StringLiteral:
+		if v.Token.Type != token.STRING {
+			return nil, fmt.Errorf("unsupported %s literal %s", v.Token.Type, v.Token.Literal)
+		}
+		return v.Decode(), nil
+	case *ast.BooleanLiteral:
+		return v.Value(), nil
+	case *ast.NullLiteral:
+		return nil, nil
+	case *ast.Identifier:
+		value, ok := env[v.Value]
+		if !ok {
+			return nil, fmt.Errorf("undefined identifier %s", v.Value)
+		}
+		return normalize(v.Value, value)
+	case *ast.PrefixExpression:
+		return evalPrefix(v, env)
+	case *ast.InfixExpression:
+		return evalInfix(v, env)
+	case *ast.IsExpression:
+		return evalIs(v, env)
+	default:
+		return nil, fmt.Errorf("unsupported expression %s", expr.String())
+	}
+}
+
+// Integers within the int64 range are int64, other numbers float64
+func numberValue(n *ast.NumberLiteral) (any, error) {
+	if i, ok := n.IntValue(); ok {
+		return i, nil
+	}
+
+	return n.Value()
+}
+
+func normalize(name string, value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, string, int64, float64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported value of %s: %T", name, value)
+	}
+}
+
+func evalPrefix(expr *ast.PrefixExpression, env Env) (any, error) {
+	right, err := eval(expr.Right, env)
+	if err != nil || right == nil {
+		return nil, err
+	}
+
+	switch expr.Token.Type {
+	case token.NOT:
+		b, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("NOT requires a boolean, got %T", right)
+		}
+		return !b, nil
+	case token.PLUS, token.MINUS:
+		switch v := right.(type) {
+		case int64:
+			if expr.Token.Type == token.MINUS {
+				if v == math.MinInt64 {
+					return nil, fmt.Errorf("-(%d) is out of the int64 range", v)
+				}
+				return -v, nil
+			}
+			return v, nil
+		case float64:
+			if expr.Token.Type == token.MINUS {
+				return -v, nil
+			}
+			return v, nil
+		default:
+			return nil, fmt.Errorf("%s requires a number, got %T", expr.Token.Type, right)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", expr.Symbol())
+	}
+}
+
+func evalInfix(expr *ast.InfixExpression, env Env) (any, error) {
+	switch expr.Operator() {
+	case token.AND, token.OR:
+		return evalLogical(expr, env)
+	case token.IS, token.IS_NOT:
+		return nil, fmt.Errorf("unsupported %s %s", expr.Symbol(), expr.Right.String())
+	}
+
+	left, err := eval(expr.Left, env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := eval(expr.Right, env)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch expr.Operator() {
+	case token.PLUS, token.MINUS, token.ASTERISK, token.SLASH, token.MOD:
+		return arithmetic(expr.Operator(), left, right)
+	case token.EQ, token.BANG_EQ, token.NOT_EQ, token.LT, token.LT_EQ, token.GT, token.GT_EQ:
+		return compare(expr.Operator(), left, right)
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", expr.Symbol())
+	}
+}
+
+// AND and OR with the three-valued logic, `NULL AND FALSE` is FALSE
+func evalLogical(expr *ast.InfixExpression, env Env) (any, error) {
+	left, err := evalBoolean(expr.Left, env, expr.Operator())
+	if err != nil {
+		return nil, err
+	}
+	shortCircuit := expr.Operator() == token.OR
+	if left != nil && *left == shortCircuit {
+		return shortCircuit, nil
+	}
+
+	right, err := evalBoolean(expr.Right, env, expr.Operator())
+	if err != nil {
+		return nil, err
+	}
+	if right != nil && *right == shortCircuit {
+		return shortCircuit, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	return !shortCircuit, nil
+}
+
+func evalBoolean(expr ast.Expression, env Env, op token.Type) (*bool, error) {
+	value, err := eval(expr, env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s requires booleans, got %T", op, value)
+	}
+
+	return &b, nil
+}
+
+// `x IS [NOT] NULL`, `x IS [NOT] TRUE` and `x IS [NOT] FALSE`, which are never NULL
+func evalIs(expr *ast.IsExpression, env Env) (any, error) {
+	left, err := eval(expr.Left, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var is bool
+	switch expr.Predicate {
+	case token.NULL:
+		is = left == nil
+	default:
+		b, ok := left.(bool)
+		is = ok && b == (expr.Predicate == token.TRUE)
+	}
+
+	if expr.Negated {
+		return !is, nil
+	}
+
+	return is, nil
+}
+
+func arithmetic(op token.Type, left, right any) (any, error) {
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		return intArithmetic(op, l, r)
+	}
+
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s requires numbers, got %T and %T", op, left, right)
+	}
+
+	switch op {
+	case token.PLUS:
+		return lf + rf, nil
+	case token.MINUS:
+		return lf - rf, nil
+	case token.ASTERISK:
+		return lf * rf, nil
+	default:
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if op == token.SLASH {
+			return lf / rf, nil
+		}
+		return math.Mod(lf, rf), nil
+	}
+}
+
+// Integer arithmetic is an error instead of wrapping around on overflow, like Postgres
+func intArithmetic(op token.Type, l, r int64) (any, error) {
+	var result int64
+	var ok bool
+	switch op {
+	case token.PLUS:
+		result = l + r
+		ok = (result > l) == (r > 0)
+	case token.MINUS:
+		result = l - r
+		ok = (result < l) == (r > 0)
+	case token.ASTERISK:
+		result = l * r
+		ok = l == 0 || (result/l == r && !(l == -1 && r == math.MinInt64))
+	default:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if op == token.MOD {
+			return l % r, nil
+		}
+		result = l / r
+		ok = !(l == math.MinInt64 && r == -1)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%d %s %d is out of the int64 range", l, op, r)
+	}
+
+	return result, nil
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func compare(op token.Type, left, right any) (any, error) {
+	var cmp int
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		cmp = compareOrdered(l, r)
+	} else if lf, ok := toFloat(left); ok {
+		rf, ok := toFloat(right)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %T with %T", left, right)
+		}
+		cmp = compareOrdered(lf, rf)
+	} else {
+		switch l := left.(type) {
+		case string:
+			r, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot compare %T with %T", left, right)
+			}
+			cmp = strings.Compare(l, r)
+		case bool:
+			r, ok := right.(bool)
+			if !ok {
+				return nil, fmt.Errorf("cannot compare %T with %T", left, right)
+			}
+			// FALSE < TRUE
+			cmp = compareOrdered(boolToInt(l), boolToInt(r))
+		}
+	}
+
+	switch op {
+	case token.EQ:
+		return cmp == 0, nil
+	case token.BANG_EQ, token.NOT_EQ:
+		return cmp != 0, nil
+	case token.LT:
+		return cmp < 0, nil
+	case token.LT_EQ:
+		return cmp <= 0, nil
+	case token.GT:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func compareOrdered[T int | int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package eval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	program, err := Compile("a * b + 1")
+	if err != nil {
+		t.Fatalf("Compile() failed: %s", err)
+	}
+
+	type TestCase struct {
+		env      Env
+		expected any
+	}
+
+	inputs := []TestCase{
+		{Env{"a": 2, "b": 3}, int64(7)},
+		{Env{"a": int64(-4), "b": 5}, int64(-19)},
+		{Env{"a": 1.5, "b": 2}, 4.0},
+		{Env{"a": nil, "b": 2}, nil},
+	}
+	for _, input := range inputs {
+		actual, err := program.Eval(input.env)
+		if err != nil {
+			t.Fatalf("Eval(%v) failed: %s", input.env, err)
+		}
+		if actual != input.expected {
+			t.Errorf("Eval(%v) not %v (%T), got %v (%T)", input.env, input.expected, input.expected, actual, actual)
+		}
+	}
+
+	if _, err := program.Eval(Env{"a": 1}); err == nil {
+		t.Errorf("Eval() without b should be an error")
+	}
+	if _, err := program.Eval(Env{"a": "x", "b": 1}); err == nil {
+		t.Errorf("Eval() of a string operand should be an error")
+	}
+
+	errInputs := []string{"", "a +", "a, b", "x = 1 )"}
+	for _, input := range errInputs {
+		if _, err := Compile(input); err == nil {
+			t.Errorf("Compile(%q) should be an error", input)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected any
+	}
+
+	env := Env{"x": 10, "y": 2.5, "s": "abc", "t": true, "f": false, "n": nil, "max": int64(math.MaxInt64), "min": int64(math.MinInt64)}
+	inputs := []TestCase{
+		{"1", int64(1)},
+		{"0x1f", int64(31)},
+		{"010", int64(8)},
+		{"010 + 0b11", int64(11)},
+		{"01000000000000000000000", 9223372036854775808.0},
+		{"9223372036854775808", 9223372036854775808.0},
+		{"max - 1 + 1", int64(math.MaxInt64)},
+		{"min + 1 - 1", int64(math.MinInt64)},
+		{"-max - 1", int64(math.MinInt64)},
+		{"min * 1", int64(math.MinInt64)},
+		{"max * -1", -int64(math.MaxInt64)},
+		{"min % -1", int64(0)},
+		{"1.5e1", 15.0},
+		{"'it''s'", "it's"},
+		{"NULL", nil},
+		{"-x + +y", -7.5},
+		{"x / 3", int64(3)},
+		{"x / 4.0", 2.5},
+		{"x % 3", int64(1)},
+		{"x > y AND s = 'abc'", true},
+		{"s < 'abd'", true},
+		{"x <> 10 OR NOT t", false},
+		{"x = 10.0", true},
+		{"n = 1", nil},
+		{"n + 1", nil},
+		{"n AND f", false},
+		{"n OR t", true},
+		{"n AND t", nil},
+		{"n IS NULL", true},
+		{"x IS NOT NULL", true},
+		{"t IS TRUE", true},
+		{"n IS NOT FALSE", true},
+	}
+	for _, input := range inputs {
+		program, err := Compile(input.input)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %s", input.input, err)
+		}
+		actual, err := program.Eval(env)
+		if err != nil {
+			t.Fatalf("Eval(%q) failed: %s", input.input, err)
+		}
+		if actual != input.expected {
+			t.Errorf("Eval(%q) not %v (%T), got %v (%T)", input.input, input.expected, input.expected, actual, actual)
+		}
+	}
+
+	errInputs := []string{
+		"x / 0",
+		"x % 0",
+		"max + 1",
+		"min - 1",
+		"max * 2",
+		"min * -1",
+		"-1 * min",
+		"min / -1",
+		"-min",
+		"1e400",
+		"s + 1",
+		"s = 1",
+		"NOT x",
+		"x AND t",
+		"f(x)",
+		"undefined",
+	}
+	for _, input := range errInputs {
+		program, err := Compile(input)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %s", input, err)
+		}
+		if _, err := program.Eval(env); err == nil {
+			t.Errorf("Eval(%q) should be an error", input)
+		}
+	}
+}
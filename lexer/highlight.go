@@ -0,0 +1,59 @@
+package lexer
+
+import "github.com/chenjunwen186/sqlexpr/token"
+
+// TokenInfo is a token with its highlighting category and range in the input
+type TokenInfo struct {
+	Type     token.Type
+	Category string
+	Text     string // The source text of the token, even for illegal tokens
+	// The range of the token in the input as rune offsets, [Start, End)
+	Start, End int
+}
+
+// Highlight lexes the whole input for syntax highlighting, EOF excluded.
+// Illegal tokens are included with `token.CategoryError`
+// instead of stopping the lexer, the error of the first one is returned
+// along with every token.
+func Highlight(input string) ([]TokenInfo, error) {
+	l := New(input)
+
+	var infos []TokenInfo
+	var err error
+	for {
+		tok := l.NextToken()
+		if tok.IsEOF() {
+			return infos, err
+		}
+		if err == nil {
+			err = tok.IsError()
+		}
+
+		infos = append(infos, TokenInfo{
+			Type:     tok.Type,
+			Category: tok.Type.Category(),
+			Text:     string(l.input[tok.Start:tok.End]),
+			Start:    tok.Start,
+			End:      tok.End,
+		})
+	}
+}
+
+// Lint lexes the whole input without parsing it and returns the error of every illegal token,
+// like a `;` or a comment, for rejecting input cheaply before a full parse.
+// The result is empty when the input has no illegal tokens,
+// which does not mean the input parses.
+func Lint(input string) []error {
+	l := New(input)
+
+	var errs []error
+	for {
+		tok := l.NextToken()
+		if tok.IsEOF() {
+			return errs
+		}
+		if err := tok.IsError(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+func TestHighlight(t *testing.T) {
+	input := "f(`a`, 'x') IS  NOT NULL and 'é' >= 1.5 -- note"
+	expected := []TokenInfo{
+		{token.IDENT, token.CategoryIdentifier, "f", 0, 1},
+		{token.LPAREN, token.CategoryPunctuation, "(", 1, 2},
+		{token.BACK_QUOTE_IDENT, token.CategoryIdentifier, "`a`", 2, 5},
+		{token.COMMA, token.CategoryPunctuation, ",", 5, 6},
+		{token.STRING, token.CategoryString, "'x'", 7, 10},
+		{token.RPAREN, token.CategoryPunctuation, ")", 10, 11},
+		{token.IS_NOT, token.CategoryKeyword, "IS  NOT", 12, 19},
+		{token.NULL, token.CategoryKeyword, "NULL", 20, 24},
+		{token.AND, token.CategoryKeyword, "and", 25, 28},
+		{token.STRING, token.CategoryString, "'é'", 29, 32},
+		{token.GT_EQ, token.CategoryOperator, ">=", 33, 35},
+		{token.NUMBER, token.CategoryNumber, "1.5", 36, 39},
+		{token.ILLEGAL, token.CategoryError, "-- note", 40, 47},
+	}
+
+	infos, err := Highlight(input)
+	if err == nil || err.Error() != `1:41: not support SQL comment: "-- note"` {
+		t.Errorf("err wrong, got %v", err)
+	}
+	if !reflect.DeepEqual(infos, expected) {
+		t.Errorf("Highlight(%q) wrong.\nexpected=%v\ngot=     %v", input, expected, infos)
+	}
+
+	infos, err = Highlight("a + 'unclosed")
+	if err == nil {
+		t.Errorf("err should not be nil")
+	}
+	if len(infos) != 3 || infos[2].Category != token.CategoryError || infos[2].Text != "'unclosed" {
+		t.Errorf("Highlight() wrong, got %v", infos)
+	}
+}
+
+func TestLint(t *testing.T) {
+	type TestCase struct {
+		input string
+		errs  []string
+	}
+
+	inputs := []TestCase{
+		{"a + b", nil},
+		{"", nil},
+		{"a +", nil},
+		{"a; b", []string{"1:2: not support token `;`"}},
+		{"a; b -- note", []string{"1:2: not support token `;`", `1:6: not support SQL comment: "-- note"`}},
+		{"SELECT 1", []string{`1:1: not support keyword: "SELECT"`}},
+		{"a\n  ; b", []string{"2:3: not support token `;`"}},
+	}
+	for _, input := range inputs {
+		errs := Lint(input.input)
+		if len(errs) != len(input.errs) {
+			t.Errorf("Lint(%q) wrong, expected %q, got %v", input.input, input.errs, errs)
+			continue
+		}
+		for i, err := range errs {
+			if err.Error() != input.errs[i] {
+				t.Errorf("Lint(%q)[%d] not %q, got %q", input.input, i, input.errs[i], err.Error())
+			}
+		}
+	}
+}
@@ -4,12 +4,56 @@ import (
 	"bytes"
 	"fmt"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/chenjunwen186/sqlexpr/token"
 )
 
 var EOF rune = 0
 
+type Options struct {
+	// Emit `token.WHITESPACE` tokens carrying the exact run of
+	// spaces/tabs/newlines instead of skipping them.
+	// Whitespace inside merged tokens like `IS NOT` is still absorbed.
+	EmitWhitespace bool
+
+	// Lex `$` immediately followed by a digit, like `$100.50`,
+	// as a `token.NUMBER` with the `$` stripped.
+	// `$` in any other position stays illegal,
+	// so dollar-quoted strings (`$$...$$`) are never mistaken for money.
+	// Positional parameters like `$1` are not supported by this lexer,
+	// with this option on they are read as the number `1`.
+	CurrencyLiterals bool
+
+	// Extra characters allowed in identifiers, like `$`, `@` or `#`
+	// for `$col`, `@var` or `#temp`. They are allowed anywhere in an identifier,
+	// but only start one when directly followed by another identifier character,
+	// otherwise they keep their usual meaning.
+	// A currency literal like `$100` takes precedence over an identifier `$100`.
+	// A listed `#` takes precedence over MySQL `#` comments,
+	// `#temp` and `a#b` are identifiers while `# temp` is still a comment.
+	// Only letters, digits, `$`, `@`, `#` and `_` are allowed, other characters are ignored,
+	// so comment starts like `--` or `/*`, quotes and `;` keep their meaning.
+	ExtraIdentifierChars []rune
+
+	// Treat a backslash in quoted identifiers as an escape of the next character,
+	// so `a\`b` is a single identifier containing a backtick.
+	// By default only a doubled delimiter escapes it, like `a``b`,
+	// and a backslash is an ordinary character.
+	BackslashEscapesInIdentifiers bool
+
+	// Lex the Postgres jsonb path operators `#>` and `#>>`.
+	// By default `#` always starts a MySQL comment, which is illegal unless `AllowComments`,
+	// so `a = 1 #> b` can't hide a comment `#> b` from a MySQL backend.
+	JSONPathOperators bool
+
+	// Emit `token.COMMENT` tokens for `-- ...`, `# ...` and `/* ... */` comments,
+	// carrying the comment with its delimiters but without the ending newline.
+	// Comments are illegal tokens by default to reduce the SQL injection risk,
+	// an unclosed `/*` and a stray `*/` are illegal regardless.
+	AllowComments bool
+}
+
 type Lexer struct {
 	input        []rune
 	position     int
@@ -18,22 +62,82 @@ type Lexer struct {
 	preChar rune
 	char    rune
 
+	// The 1-based line and column of `char`
+	line, column int
+
 	nextToken token.Token
+	// The tokens after `nextToken` up to the first one which is not whitespace or a comment,
+	// read ahead when merging tokens like `IS NOT`
+	bufferedTokens []token.Token
+
+	opts Options
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: []rune(input)}
+	return NewWithOptions(input, Options{})
+}
+
+func NewWithOptions(input string, opts Options) *Lexer {
+	return newLexer([]rune(input), opts)
+}
+
+// NewBytes is like `New` for UTF-8 input in a byte slice,
+// it decodes the runes directly without copying the input to a string first
+func NewBytes(input []byte) *Lexer {
+	return NewBytesWithOptions(input, Options{})
+}
+
+func NewBytesWithOptions(input []byte, opts Options) *Lexer {
+	return newLexer(decodeRunes(input), opts)
+}
+
+func newLexer(input []rune, opts Options) *Lexer {
+	l := &Lexer{input: input, opts: opts, line: 1}
 	l.readChar()
 
-	l.nextToken = l.move()
+	l.nextToken = l.next()
 	return l
 }
 
+// Decodes like `[]rune(string(b))`, invalid UTF-8 bytes become `utf8.RuneError`
+func decodeRunes(b []byte) []rune {
+	runes := make([]rune, 0, len(b))
+	for i := 0; i < len(b); {
+		if b[i] < utf8.RuneSelf {
+			runes = append(runes, rune(b[i]))
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(b[i:])
+		runes = append(runes, r)
+		i += size
+	}
+
+	return runes
+}
+
+// Options returns the options the lexer was created with
+func (l *Lexer) Options() Options {
+	return l.opts
+}
+
 func (l *Lexer) Len() int {
 	return len(l.input)
 }
 
 func (l *Lexer) readChar() {
+	// The position stays at EOF once reached,
+	// a `\r` followed by `\n` ends the line at the `\n`
+	if l.nextPosition <= len(l.input) {
+		if l.char == '\n' || l.char == '\r' && l.peekChar() != '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
+
 	l.preChar = l.char
 	if l.nextPosition >= len(l.input) {
 		l.char = EOF
@@ -61,9 +165,20 @@ func (l *Lexer) isWhitespace() bool {
 	return l.char == ' ' || l.char == '\t' || l.char == '\n' || l.char == '\r'
 }
 
-// Start with [\d]
-// Support 0 100 1.0 2e2 1.23e3 0.23e-3 0.1e+3 12. 1.e3 0e+3, 0b01, 0x1af 0765
-// Not support 1e 1e+ 1e- 1e1.2 1e1e2 .12
+func (l *Lexer) readWhitespace() token.Token {
+	var b bytes.Buffer
+
+	for l.isWhitespace() {
+		b.WriteRune(l.char)
+		l.readChar()
+	}
+
+	return token.Token{Type: token.WHITESPACE, Literal: b.String()}
+}
+
+// Start with [\d] or `.` followed by [\d]
+// Support 0 100 1.0 2e2 1.23e3 0.23e-3 0.1e+3 12. 1.e3 0e+3, 0b01, 0x1af 0765 .12
+// Not support 1e 1e+ 1e- 1e1.2 1e1e2
 // 1e+3+3 => ((1e+3)+3)
 func (l *Lexer) readNumber() token.Token {
 	var b bytes.Buffer
@@ -253,6 +368,59 @@ func (l *Lexer) readString() token.Token {
 	return token.Token{Type: token.STRING, Literal: b.String()}
 }
 
+// Start with [xXbB]'
+func (l *Lexer) readTypedString() token.Token {
+	var b bytes.Buffer
+
+	tokenType := token.Type(token.HEX_STRING)
+	name := "hexadecimal"
+	isValidDigit := func(char rune) bool {
+		return (char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+	}
+	if l.char == 'b' || l.char == 'B' {
+		tokenType = token.BIT_STRING
+		name = "bit"
+		isValidDigit = func(char rune) bool {
+			return char == '0' || char == '1'
+		}
+	}
+
+	b.WriteRune(l.char) // Write `x`, `X`, `b` or `B`
+	l.readChar()
+	b.WriteRune(l.char) // Write `'`
+	l.readChar()
+
+	var (
+		digits    int
+		isIllegal bool
+	)
+	for l.char != '\'' {
+		if l.char == EOF {
+			return token.NewIllegalToken(fmt.Sprintf("unexpected EOF: %s", b.String()))
+		}
+
+		if !isValidDigit(l.char) {
+			isIllegal = true
+		}
+		digits++
+
+		b.WriteRune(l.char)
+		l.readChar()
+	}
+	b.WriteRune(l.char) // Write `'`
+
+	// Each byte takes two hexadecimal digits
+	if tokenType == token.HEX_STRING && digits%2 != 0 {
+		isIllegal = true
+	}
+
+	if isIllegal {
+		return token.NewIllegalToken(fmt.Sprintf("invalid %s string literal: %q", name, b.String()))
+	}
+
+	return token.Token{Type: tokenType, Literal: b.String()}
+}
+
 func (l *Lexer) readBackQuoteIdentifier() token.Token {
 	var b bytes.Buffer
 
@@ -280,7 +448,7 @@ func (l *Lexer) readBackQuoteIdentifier() token.Token {
 			break
 		}
 
-		if l.char == '\\' && !isPreValidEscape {
+		if l.opts.BackslashEscapesInIdentifiers && l.char == '\\' && !isPreValidEscape {
 			isPreValidEscape = true
 		} else {
 			isPreValidEscape = false
@@ -321,7 +489,7 @@ func (l *Lexer) readDoubleQuoteIdentifier() token.Token {
 			break
 		}
 
-		if l.char == '\\' && !isPreValidEscape {
+		if l.opts.BackslashEscapesInIdentifiers && l.char == '\\' && !isPreValidEscape {
 			isPreValidEscape = true
 		} else {
 			isPreValidEscape = false
@@ -337,7 +505,7 @@ func (l *Lexer) readDoubleQuoteIdentifier() token.Token {
 func (l *Lexer) readIdentifier() string {
 	var b bytes.Buffer
 
-	for isIdentifier(l.char) || unicode.IsDigit(l.char) {
+	for isIdentifier(l.char) || unicode.IsDigit(l.char) || l.isExtraIdentifierChar(l.char) {
 		b.WriteRune(l.char)
 		l.readChar()
 	}
@@ -372,6 +540,9 @@ func (l *Lexer) readSingleLineComment() token.Token {
 		b.WriteRune(l.char)
 	}
 
+	if l.opts.AllowComments {
+		return token.Token{Type: token.COMMENT, Literal: b.String()}
+	}
 	// Do not support `--` or `#` token to reduce SQL injection risk.
 	return token.NewIllegalToken(fmt.Sprintf(`not support SQL comment: "%s"`, b.String()))
 }
@@ -403,6 +574,9 @@ func (l *Lexer) readMultilineComment() token.Token {
 		b.WriteRune(l.char)
 	}
 
+	if l.opts.AllowComments {
+		return token.Token{Type: token.COMMENT, Literal: b.String()}
+	}
 	// Do not support `/* */` token to reduce SQL injection risk.
 	return token.NewIllegalToken(fmt.Sprintf(`not support SQL comment: "%s"`, b.String()))
 }
@@ -428,6 +602,51 @@ func (l *Lexer) isIdentifierStart() bool {
 	return false
 }
 
+// x'4F' or b'0101'
+func (l *Lexer) isTypedStringStart() bool {
+	switch l.char {
+	case 'x', 'X', 'b', 'B':
+		return l.peekChar() == '\''
+	default:
+		return false
+	}
+}
+
+func (l *Lexer) isExtraIdentifierChar(char rune) bool {
+	if !isSafeIdentifierChar(char) {
+		return false
+	}
+	for _, c := range l.opts.ExtraIdentifierChars {
+		if c == char {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The characters `Options.ExtraIdentifierChars` may add,
+// none of them starts a string or ends a statement,
+// `#` only starts a comment when not followed by an identifier character
+func isSafeIdentifierChar(char rune) bool {
+	return char == '$' || char == '@' || char == '#' || char == '_' || unicode.IsLetter(char) || unicode.IsDigit(char)
+}
+
+// Whether the current character is an extra identifier character
+// starting an identifier, see `Options.ExtraIdentifierChars`
+func (l *Lexer) isExtraIdentifierStart() bool {
+	if !l.isExtraIdentifierChar(l.char) {
+		return false
+	}
+
+	peekChar := l.peekChar()
+	if l.opts.CurrencyLiterals && l.char == '$' && unicode.IsDigit(peekChar) {
+		return false
+	}
+
+	return isIdentifier(peekChar) || l.isExtraIdentifierChar(peekChar)
+}
+
 func isLetter(char rune) bool {
 	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z'
 }
@@ -438,34 +657,113 @@ func newToken(tokenType token.Type, ch rune) token.Token {
 
 func (l *Lexer) NextToken() token.Token {
 	tok := l.nextToken
-	l.nextToken = l.move()
+	l.nextToken = l.next()
+
+	// Only `IS` and `NOT` start a merged token, skip peeking for the others
+	if tok.Type != token.IS && tok.Type != token.NOT {
+		return tok
+	}
 
 	// Read token `NOT IN`, `NOT BETWEEN`, `NOT LIKE`, `IS NOT`
 	// All these tokens are treated as one token
-	if tok.Type == token.IS && l.nextToken.Type == token.NOT { // Read token `IS NOT`
-		tok = token.Token{Type: token.IS_NOT, Literal: "IS NOT"}
-		l.nextToken = l.move()
+	peekToken := l.peekSignificantToken()
+	if tok.Type == token.IS && peekToken.Type == token.NOT { // Read token `IS NOT`
+		tok = token.Token{Type: token.IS_NOT, Literal: "IS NOT", Start: tok.Start, End: peekToken.End, Line: tok.Line, Column: tok.Column}
+		l.skipSignificantToken()
 		return tok
-	} else if tok.Type == token.NOT && l.nextToken.Type == token.IN { // Read token `NOT IN`
-		tok = token.Token{Type: token.NOT_IN, Literal: "NOT IN"}
-		l.nextToken = l.move()
+	} else if tok.Type == token.NOT && peekToken.Type == token.IN { // Read token `NOT IN`
+		tok = token.Token{Type: token.NOT_IN, Literal: "NOT IN", Start: tok.Start, End: peekToken.End, Line: tok.Line, Column: tok.Column}
+		l.skipSignificantToken()
 		return tok
-	} else if tok.Type == token.NOT && l.nextToken.Type == token.BETWEEN { // Read token `NOT BETWEEN`
-		tok = token.Token{Type: token.NOT_BETWEEN, Literal: "NOT BETWEEN"}
-		l.nextToken = l.move()
+	} else if tok.Type == token.NOT && peekToken.Type == token.BETWEEN { // Read token `NOT BETWEEN`
+		tok = token.Token{Type: token.NOT_BETWEEN, Literal: "NOT BETWEEN", Start: tok.Start, End: peekToken.End, Line: tok.Line, Column: tok.Column}
+		l.skipSignificantToken()
 		return tok
-	} else if tok.Type == token.NOT && l.nextToken.Type == token.LIKE { // Read token `NOT LIKE`
-		tok = token.Token{Type: token.NOT_LIKE, Literal: "NOT LIKE"}
-		l.nextToken = l.move()
+	} else if tok.Type == token.NOT && peekToken.Type == token.LIKE { // Read token `NOT LIKE`
+		tok = token.Token{Type: token.NOT_LIKE, Literal: "NOT LIKE", Start: tok.Start, End: peekToken.End, Line: tok.Line, Column: tok.Column}
+		l.skipSignificantToken()
 		return tok
 	}
 
 	return tok
 }
 
+// Returns the next token that is not `token.WHITESPACE` or `token.COMMENT`
+// without consuming it
+func (l *Lexer) peekSignificantToken() token.Token {
+	if !isInsignificant(l.nextToken.Type) {
+		return l.nextToken
+	}
+
+	for i := 0; ; i++ {
+		if i == len(l.bufferedTokens) {
+			l.bufferedTokens = append(l.bufferedTokens, l.move())
+		}
+		if !isInsignificant(l.bufferedTokens[i].Type) {
+			return l.bufferedTokens[i]
+		}
+	}
+}
+
+// Consumes the token returned by `peekSignificantToken` and the whitespace before it,
+// the comments before it are still returned after the merged token
+func (l *Lexer) skipSignificantToken() {
+	var comments []token.Token
+	for tok := l.nextToken; isInsignificant(tok.Type); tok = l.next() {
+		if tok.Type == token.COMMENT {
+			comments = append(comments, tok)
+		}
+	}
+	l.bufferedTokens = append(comments, l.bufferedTokens...)
+	l.nextToken = l.next()
+}
+
+func isInsignificant(t token.Type) bool {
+	return t == token.WHITESPACE || t == token.COMMENT
+}
+
+func (l *Lexer) next() token.Token {
+	if len(l.bufferedTokens) > 0 {
+		tok := l.bufferedTokens[0]
+		l.bufferedTokens = l.bufferedTokens[1:]
+		return tok
+	}
+
+	return l.move()
+}
+
 func (l *Lexer) move() token.Token {
+	if !l.opts.EmitWhitespace {
+		l.skipWhitespace()
+	}
+
+	start, line, column := l.offset(), l.line, l.column
+	tok := l.read()
+	tok.Start, tok.End = start, l.offset()
+	tok.Line, tok.Column = line, column
+
+	return tok
+}
+
+// The offset of the current char, which is len(input) at EOF
+func (l *Lexer) offset() int {
+	if l.position > len(l.input) {
+		return len(l.input)
+	}
+
+	return l.position
+}
+
+func (l *Lexer) read() token.Token {
 	var tok token.Token
-	l.skipWhitespace()
+	if l.isWhitespace() {
+		return l.readWhitespace()
+	}
+
+	if l.isExtraIdentifierStart() { // Read token `IDENT` like `$col`, `@var` or `#temp`
+		tok = token.LookupIdent(l.readIdentifier())
+		return tok
+	}
 
 	switch l.char {
 	case '|':
@@ -477,7 +775,12 @@ func (l *Lexer) move() token.Token {
 		}
 
 	case '=':
-		tok = newToken(token.EQ, l.char)
+		if l.peekChar() == '>' { // Read token `=>`
+			l.readChar()
+			tok = token.Token{Type: token.EQ_GT, Literal: "=>"}
+		} else { // Read token `=`
+			tok = newToken(token.EQ, l.char)
+		}
 
 	case '!':
 		if l.peekChar() == '=' { // Read token `!=`
@@ -508,12 +811,26 @@ func (l *Lexer) move() token.Token {
 		tok = newToken(token.PLUS, l.char)
 
 	case '#':
-		tok = l.readSingleLineComment()
+		// `#` starts a comment, only with the option `JSONPathOperators` `#>` and `#>>` are operators
+		if l.opts.JSONPathOperators && l.peekChar() == '>' { // Read token `#>` or `#>>`
+			l.readChar()
+			if l.peekChar() == '>' { // Read token `#>>`
+				l.readChar()
+				tok = token.Token{Type: token.HASH_GT2, Literal: "#>>"}
+			} else { // Read token `#>`
+				tok = token.Token{Type: token.HASH_GT, Literal: "#>"}
+			}
+		} else {
+			tok = l.readSingleLineComment()
+		}
 
 	case ';':
 		// Do not support token `;` to reduce SQL injection risk.
 		tok = token.NewIllegalToken("not support token `;`")
 	case '-':
+		// `--` is a comment only when the two `-` are adjacent,
+		// `a--b` and `a-- b` are comments while `a - -b`, `a- -b` or `a -\n-b`
+		// are a minus followed by a negation, because whitespace separates the tokens
 		if l.peekChar() == '-' { // Read token `--`
 			tok = l.readSingleLineComment()
 		} else if l.peekChar() == '>' { // Read token `->` or `->>`
@@ -546,7 +863,19 @@ func (l *Lexer) move() token.Token {
 	case '~':
 		tok = newToken(token.TILDE, l.char)
 	case '&':
-		tok = newToken(token.AMP, l.char)
+		if l.peekChar() == '&' { // Read token `&&`
+			l.readChar()
+			tok = token.Token{Type: token.AMP2, Literal: "&&"}
+		} else { // Read token `&`
+			tok = newToken(token.AMP, l.char)
+		}
+	case '@':
+		if l.peekChar() == '>' { // Read token `@>`
+			l.readChar()
+			tok = token.Token{Type: token.AT_GT, Literal: "@>"}
+		} else {
+			tok = newToken(token.ILLEGAL, l.char)
+		}
 	case '^':
 		tok = newToken(token.XOR, l.char)
 
@@ -565,6 +894,9 @@ func (l *Lexer) move() token.Token {
 		} else if l.peekChar() == '<' { // Read token `<<`
 			l.readChar()
 			tok = token.Token{Type: token.LT2, Literal: "<<"}
+		} else if l.peekChar() == '@' { // Read token `<@`
+			l.readChar()
+			tok = token.Token{Type: token.LT_AT, Literal: "<@"}
 		} else { // Read token `<`
 			tok = newToken(token.LT, l.char)
 		}
@@ -581,6 +913,10 @@ func (l *Lexer) move() token.Token {
 		}
 
 	case '.':
+		if unicode.IsDigit(l.peekChar()) { // Read token `NUMBER` like `.12`
+			tok = l.readNumber()
+			return tok
+		}
 		tok = newToken(token.PERIOD, l.char)
 
 	case '\'':
@@ -592,7 +928,23 @@ func (l *Lexer) move() token.Token {
 		tok = l.readDoubleQuoteIdentifier()
 
 	case '?':
-		tok = newToken(token.QUESTION, l.char)
+		if l.peekChar() == '|' { // Read token `?|`
+			l.readChar()
+			tok = token.Token{Type: token.QUESTION_PIPE, Literal: "?|"}
+		} else if l.peekChar() == '&' { // Read token `?&`
+			l.readChar()
+			tok = token.Token{Type: token.QUESTION_AMP, Literal: "?&"}
+		} else { // Read token `?`
+			tok = newToken(token.QUESTION, l.char)
+		}
+
+	case '$':
+		if l.opts.CurrencyLiterals && unicode.IsDigit(l.peekChar()) { // Read token `NUMBER` like `$100`
+			l.readChar() // Skip `$`
+			tok = l.readNumber()
+			return tok
+		}
+		tok = newToken(token.ILLEGAL, l.char)
 
 	case ':':
 		if l.peekChar() == ':' { // Read token `::`
@@ -610,6 +962,10 @@ func (l *Lexer) move() token.Token {
 		if unicode.IsDigit(l.char) { // Read token `NUMBER`
 			tok = l.readNumber()
 			return tok
+		} else if l.isTypedStringStart() { // Read token `HEX_STRING` or `BIT_STRING`
+			tok = l.readTypedString()
+			l.readChar() // Skip the closing `'`
+			return tok
 		} else if l.isIdentifierStart() { // Read token `IDENT` or `KEYWORD`
 			ident := l.readIdentifier()
 			tok = token.LookupIdent(ident) // Lookup `KEYWORD`
@@ -120,6 +120,29 @@ func TestStringLiteral(t *testing.T) {
 	illegalCases.testAll(t, "TestStringLiteral")
 }
 
+func TestTypedStringLiteral(t *testing.T) {
+	input := `x'48656C6C6F' X'' b'0101' B'1' x'4g' X'123' b'012' x 'a' xb'1' b'0`
+	expected := ExpectedLiterals{
+		{token.HEX_STRING, `x'48656C6C6F'`},
+		{token.HEX_STRING, `X''`},
+		{token.BIT_STRING, `b'0101'`},
+		{token.BIT_STRING, `B'1'`},
+		{token.ILLEGAL, `invalid hexadecimal string literal: "x'4g'"`},
+		{token.ILLEGAL, `invalid hexadecimal string literal: "X'123'"`},
+		{token.ILLEGAL, `invalid bit string literal: "b'012'"`},
+		{token.IDENT, "x"},
+		{token.STRING, "'a'"},
+		{token.IDENT, "xb"},
+		{token.STRING, "'1'"},
+		{token.ILLEGAL, "unexpected EOF: b'0"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestTypedStringLiteral", l)
+}
+
 func TestBooleanLiteral(t *testing.T) {
 	input := `true false True False TRUE FaLSE`
 	expected := ExpectedLiterals{
@@ -203,6 +226,63 @@ func TestNumberLiteral(t *testing.T) {
 	expected.testAll(t, "TestNumberPeriodLiteral", l)
 }
 
+func TestSignChains(t *testing.T) {
+	input := `+-x -+x - -x 1e+3 1 e + 3 1e+-3 1e-+3`
+	expected := ExpectedLiterals{
+		{token.PLUS, "+"},
+		{token.MINUS, "-"},
+		{token.IDENT, "x"},
+		{token.MINUS, "-"},
+		{token.PLUS, "+"},
+		{token.IDENT, "x"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.IDENT, "x"},
+		{token.NUMBER, "1e+3"},
+		{token.NUMBER, "1"},
+		{token.IDENT, "e"},
+		{token.PLUS, "+"},
+		{token.NUMBER, "3"},
+		// The exponent takes a single sign
+		{token.ILLEGAL, `invalid number literal: "1e+"`},
+		{token.MINUS, "-"},
+		{token.NUMBER, "3"},
+		{token.ILLEGAL, `invalid number literal: "1e-"`},
+		{token.PLUS, "+"},
+		{token.NUMBER, "3"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestSignChains", l)
+}
+
+func TestCurrencyLiterals(t *testing.T) {
+	input := `$100 $1.50 $0.5e2 $ 1 $a`
+	expected := ExpectedLiterals{
+		{token.NUMBER, "100"},
+		{token.NUMBER, "1.50"},
+		{token.NUMBER, "0.5e2"},
+		{token.ILLEGAL, "$"},
+		{token.NUMBER, "1"},
+		{token.ILLEGAL, "$"},
+		{token.IDENT, "a"},
+		{token.EOF, ""},
+	}
+
+	l := NewWithOptions(input, Options{CurrencyLiterals: true})
+
+	expected.testAll(t, "TestCurrencyLiterals", l)
+
+	tokenCases := TokenCases{
+		{`$100`, token.ILLEGAL, "$"},
+		{`$$100$$`, token.ILLEGAL, "$"},
+	}
+
+	tokenCases.testAll(t, "TestCurrencyLiterals")
+}
+
 func TestIdentifiers(t *testing.T) {
 	input := `hello _world world2_ _world_ _world_0
         HELLO_WORLD HelloWorld helloWorld
@@ -222,6 +302,131 @@ func TestIdentifiers(t *testing.T) {
 	l := New(input)
 
 	expected.testAll(t, "TestIdentifiers", l)
+
+	// Letters at the boundaries of the a-z and A-Z ranges
+	input = `abc xyz Apple Zebra a z A Z aZ Za`
+	expected = ExpectedLiterals{
+		{token.IDENT, "abc"},
+		{token.IDENT, "xyz"},
+		{token.IDENT, "Apple"},
+		{token.IDENT, "Zebra"},
+		{token.IDENT, "a"},
+		{token.IDENT, "z"},
+		{token.IDENT, "A"},
+		{token.IDENT, "Z"},
+		{token.IDENT, "aZ"},
+		{token.IDENT, "Za"},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestIdentifiers", New(input))
+}
+
+func TestExtraIdentifierChars(t *testing.T) {
+	input := `$price price$usd $1 $ 1 @var a@b # comment`
+	expected := ExpectedLiterals{
+		{token.IDENT, "$price"},
+		{token.IDENT, "price$usd"},
+		{token.IDENT, "$1"},
+		{token.ILLEGAL, "$"},
+		{token.NUMBER, "1"},
+		{token.IDENT, "@var"},
+		{token.IDENT, "a@b"},
+		{token.ILLEGAL, `not support SQL comment: "# comment"`},
+		{token.EOF, ""},
+	}
+
+	l := NewWithOptions(input, Options{ExtraIdentifierChars: []rune{'$', '@'}})
+
+	expected.testAll(t, "TestExtraIdentifierChars", l)
+
+	input = `$price $100`
+	expected = ExpectedLiterals{
+		{token.IDENT, "$price"},
+		{token.NUMBER, "100"},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions(input, Options{ExtraIdentifierChars: []rune{'$'}, CurrencyLiterals: true})
+
+	expected.testAll(t, "TestExtraIdentifierChars", l)
+
+	tokenCases := TokenCases{
+		{`$price`, token.ILLEGAL, "$"},
+		{`#temp`, token.ILLEGAL, `not support SQL comment: "#temp"`},
+	}
+
+	tokenCases.testAll(t, "TestExtraIdentifierChars")
+
+	// `#` takes precedence over MySQL comments only when followed by an identifier character
+	input = "#temp ##global a#b # comment"
+	expected = ExpectedLiterals{
+		{token.IDENT, "#temp"},
+		{token.IDENT, "##global"},
+		{token.IDENT, "a#b"},
+		{token.ILLEGAL, `not support SQL comment: "# comment"`},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions(input, Options{ExtraIdentifierChars: []rune{'#'}})
+
+	expected.testAll(t, "TestExtraIdentifierChars", l)
+
+	input = "#temp #> '{a}'"
+	expected = ExpectedLiterals{
+		{token.IDENT, "#temp"},
+		{token.HASH_GT, "#>"},
+		{token.STRING, "'{a}'"},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions(input, Options{ExtraIdentifierChars: []rune{'#'}, JSONPathOperators: true})
+
+	expected.testAll(t, "TestExtraIdentifierChars", l)
+
+	// Characters which start comments or strings or end statements are ignored
+	input = "a--b\ng'h' i/*j*/ k\"l\" m-n"
+	expected = ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.ILLEGAL, `not support SQL comment: "--b"`},
+		{token.IDENT, "g"},
+		{token.STRING, "'h'"},
+		{token.IDENT, "i"},
+		{token.ILLEGAL, `not support SQL comment: "/*j*/"`},
+		{token.IDENT, "k"},
+		{token.DOUBLE_QUOTE_IDENT, `"l"`},
+		{token.IDENT, "m"},
+		{token.MINUS, "-"},
+		{token.IDENT, "n"},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions(input, Options{ExtraIdentifierChars: []rune{';', '-', '\'', '/', '*', '"'}})
+
+	expected.testAll(t, "TestExtraIdentifierChars", l)
+
+	expected = ExpectedLiterals{
+		{token.IDENT, "c"},
+		{token.ILLEGAL, "not support token `;`"},
+		{token.IDENT, "d"},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestExtraIdentifierChars", NewWithOptions("c;d", Options{ExtraIdentifierChars: []rune{';'}}))
+}
+
+func TestOrderedSetAggregates(t *testing.T) {
+	// Only keywords with the parser option `OrderedSetAggregates`
+	tokenCases := TokenCases{
+		{`within`, token.IDENT, "within"},
+		{`GROUP`, token.ILLEGAL, `not support keyword: "GROUP"`},
+		{`order`, token.ILLEGAL, `not support keyword: "order"`},
+		{`By`, token.ILLEGAL, `not support keyword: "By"`},
+		{`asc`, token.ILLEGAL, `not support keyword: "asc"`},
+		{`DESC`, token.ILLEGAL, `not support keyword: "DESC"`},
+	}
+
+	tokenCases.testAll(t, "TestOrderedSetAggregates")
 }
 
 func TestBackQuoteIdentifiers(t *testing.T) {
@@ -257,6 +462,40 @@ func TestDoubleQuoteIdentifiers(t *testing.T) {
 	expected.testAll(t, "TestDoubleQuoteIdentifiers", l)
 }
 
+func TestQuotedIdentifierEscapes(t *testing.T) {
+	input := "`a``b` `a\\` b"
+	expected := ExpectedLiterals{
+		{token.BACK_QUOTE_IDENT, "`a``b`"},
+		{token.BACK_QUOTE_IDENT, "`a\\`"},
+		{token.IDENT, "b"},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestQuotedIdentifierEscapes", New(input))
+
+	input = `"a""b" "a\" b`
+	expected = ExpectedLiterals{
+		{token.DOUBLE_QUOTE_IDENT, `"a""b"`},
+		{token.DOUBLE_QUOTE_IDENT, `"a\"`},
+		{token.IDENT, "b"},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestQuotedIdentifierEscapes", New(input))
+
+	input = "`a\\`b` `a``b` `a\\\\` \"a\\\"b\" \"a\"\"b\""
+	expected = ExpectedLiterals{
+		{token.BACK_QUOTE_IDENT, "`a\\`b`"},
+		{token.BACK_QUOTE_IDENT, "`a``b`"},
+		{token.BACK_QUOTE_IDENT, "`a\\\\`"},
+		{token.DOUBLE_QUOTE_IDENT, `"a\"b"`},
+		{token.DOUBLE_QUOTE_IDENT, `"a""b"`},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestQuotedIdentifierEscapes", NewWithOptions(input, Options{BackslashEscapesInIdentifiers: true}))
+}
+
 func TestOperators(t *testing.T) {
 	input := `
 	+
@@ -342,6 +581,168 @@ func TestOperators(t *testing.T) {
 	expected.testAll(t, "TestOperators", l)
 }
 
+// Only two adjacent `-` start a comment, a `-` separated by whitespace is a minus
+func TestHashOperators(t *testing.T) {
+	input := "data #> '{a,b}' #>> x#>y # > comment\n#>>> #"
+	expected := ExpectedLiterals{
+		{token.IDENT, "data"},
+		{token.HASH_GT, "#>"},
+		{token.STRING, "'{a,b}'"},
+		{token.HASH_GT2, "#>>"},
+		{token.IDENT, "x"},
+		{token.HASH_GT, "#>"},
+		{token.IDENT, "y"},
+		{token.ILLEGAL, `not support SQL comment: "# > comment"`},
+		{token.HASH_GT2, "#>>"},
+		{token.GT, ">"},
+		{token.ILLEGAL, `not support SQL comment: "#"`},
+		{token.EOF, ""},
+	}
+
+	l := NewWithOptions(input, Options{JSONPathOperators: true})
+
+	expected.testAll(t, "TestHashOperators", l)
+
+	// Without the option `#>` is a MySQL comment like any other `#`
+	tokenCases := TokenCases{
+		{"#> b", token.ILLEGAL, `not support SQL comment: "#> b"`},
+		{"#>> '{a}'", token.ILLEGAL, `not support SQL comment: "#>> '{a}'"`},
+	}
+
+	tokenCases.testAll(t, "TestHashOperators")
+
+	expected = ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.EQ, "="},
+		{token.NUMBER, "1"},
+		{token.ILLEGAL, `not support SQL comment: "#> b"`},
+		{token.EOF, ""},
+	}
+
+	expected.testAll(t, "TestHashOperators", New("a = 1 #> b"))
+}
+
+func TestQuestionOperators(t *testing.T) {
+	input := "data ? 'a' ?| keys ?& keys ? | ?|| ?&&"
+	expected := ExpectedLiterals{
+		{token.IDENT, "data"},
+		{token.QUESTION, "?"},
+		{token.STRING, "'a'"},
+		{token.QUESTION_PIPE, "?|"},
+		{token.IDENT, "keys"},
+		{token.QUESTION_AMP, "?&"},
+		{token.IDENT, "keys"},
+		{token.QUESTION, "?"},
+		{token.PIPE, "|"},
+		{token.QUESTION_PIPE, "?|"},
+		{token.PIPE, "|"},
+		{token.QUESTION_AMP, "?&"},
+		{token.AMP, "&"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestQuestionOperators", l)
+}
+
+func TestContainmentOperators(t *testing.T) {
+	input := "tags @> '{a}' a<@b x && y & z @ <@@>"
+	expected := ExpectedLiterals{
+		{token.IDENT, "tags"},
+		{token.AT_GT, "@>"},
+		{token.STRING, "'{a}'"},
+		{token.IDENT, "a"},
+		{token.LT_AT, "<@"},
+		{token.IDENT, "b"},
+		{token.IDENT, "x"},
+		{token.AMP2, "&&"},
+		{token.IDENT, "y"},
+		{token.AMP, "&"},
+		{token.IDENT, "z"},
+		{token.ILLEGAL, "@"},
+		{token.LT_AT, "<@"},
+		{token.AT_GT, "@>"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestContainmentOperators", l)
+}
+
+func TestNamedArgumentArrow(t *testing.T) {
+	input := "f(x => 1, y=>2) = > >= <=> =>>"
+	expected := ExpectedLiterals{
+		{token.IDENT, "f"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.EQ_GT, "=>"},
+		{token.NUMBER, "1"},
+		{token.COMMA, ","},
+		{token.IDENT, "y"},
+		{token.EQ_GT, "=>"},
+		{token.NUMBER, "2"},
+		{token.RPAREN, ")"},
+		{token.EQ, "="},
+		{token.GT, ">"},
+		{token.GT_EQ, ">="},
+		{token.LT_EQ_GT, "<=>"},
+		{token.EQ_GT, "=>"},
+		{token.GT, ">"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestNamedArgumentArrow", l)
+}
+
+func TestMinusAndComment(t *testing.T) {
+	expected := ExpectedLiterals{
+		{token.NUMBER, "1"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.NUMBER, "1"},
+		{token.NUMBER, "1"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.NUMBER, "1"},
+		{token.IDENT, "a"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.IDENT, "b"},
+		{token.IDENT, "a"},
+		{token.ILLEGAL, `not support SQL comment: "-- b"`},
+		{token.IDENT, "a"},
+		{token.ILLEGAL, `not support SQL comment: "--b"`},
+		{token.EOF, ""},
+	}
+
+	l := New("1- -1\n1 - -1\na -\t-b\na-- b\na --b")
+
+	expected.testAll(t, "TestMinusAndComment", l)
+
+	expected = ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.MINUS, "-"},
+		{token.WHITESPACE, "\r\n"},
+		{token.MINUS, "-"},
+		{token.IDENT, "b"},
+		{token.WHITESPACE, " "},
+		{token.MINUS, "-"},
+		{token.WHITESPACE, " "},
+		{token.PRT, "->"},
+		{token.WHITESPACE, " "},
+		{token.ILLEGAL, `not support SQL comment: "-->x"`},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions("a-\r\n-b - -> -->x", Options{EmitWhitespace: true})
+
+	expected.testAll(t, "TestMinusAndComment", l)
+}
+
 func TestPairs(t *testing.T) {
 	input := `
 	(
@@ -364,6 +765,184 @@ func TestPairs(t *testing.T) {
 	expected.testAll(t, "TestPairs", l)
 }
 
+func TestEmitWhitespace(t *testing.T) {
+	input := "a  +\tb\r\n IS \n NOT c"
+	expected := ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.WHITESPACE, "  "},
+		{token.PLUS, "+"},
+		{token.WHITESPACE, "\t"},
+		{token.IDENT, "b"},
+		{token.WHITESPACE, "\r\n "},
+		{token.IS_NOT, "IS NOT"},
+		{token.WHITESPACE, " "},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	l := NewWithOptions(input, Options{EmitWhitespace: true})
+
+	expected.testAll(t, "TestEmitWhitespace", l)
+
+	input = "  NOT  x"
+	expected = ExpectedLiterals{
+		{token.WHITESPACE, "  "},
+		{token.NOT, "NOT"},
+		{token.WHITESPACE, "  "},
+		{token.IDENT, "x"},
+		{token.EOF, ""},
+	}
+
+	l = NewWithOptions(input, Options{EmitWhitespace: true})
+
+	expected.testAll(t, "TestEmitWhitespace", l)
+}
+
+func TestAllowComments(t *testing.T) {
+	input := "a /* first */ + b -- rest\r\n# hash\n* c"
+	expected := ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.COMMENT, "/* first */"},
+		{token.PLUS, "+"},
+		{token.IDENT, "b"},
+		{token.COMMENT, "-- rest"},
+		{token.COMMENT, "# hash"},
+		{token.ASTERISK, "*"},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	l := NewWithOptions(input, Options{AllowComments: true})
+
+	expected.testAll(t, "TestAllowComments", l)
+
+	// Still illegal, with or without the option
+	illegal := map[string]string{
+		"a /* open":  `unexpected EOF: "/* open"`,
+		"a */ b":     "not support SQL comment `*/`",
+		"a; -- drop": "not support token `;`",
+	}
+	for input, literal := range illegal {
+		l := NewWithOptions(input, Options{AllowComments: true})
+		l.NextToken()
+		if tok := l.NextToken(); tok.Type != token.ILLEGAL || tok.Literal != literal {
+			t.Errorf("%q: token not ILLEGAL %q, got %s %q", input, literal, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestMergedTokensWithComments(t *testing.T) {
+	// The comments between the words are returned after the merged token
+	input := "x IS /* a */ NOT NULL AND y NOT -- b\n/* c */ IN (1) AND z IS NOT /* d */ NULL"
+	expected := ExpectedLiterals{
+		{token.IDENT, "x"},
+		{token.IS_NOT, "IS NOT"},
+		{token.COMMENT, "/* a */"},
+		{token.NULL, "NULL"},
+		{token.AND, "AND"},
+		{token.IDENT, "y"},
+		{token.NOT_IN, "NOT IN"},
+		{token.COMMENT, "-- b"},
+		{token.COMMENT, "/* c */"},
+		{token.LPAREN, "("},
+		{token.NUMBER, "1"},
+		{token.RPAREN, ")"},
+		{token.AND, "AND"},
+		{token.IDENT, "z"},
+		{token.IS_NOT, "IS NOT"},
+		{token.COMMENT, "/* d */"},
+		{token.NULL, "NULL"},
+		{token.EOF, ""},
+	}
+
+	for _, opts := range []Options{{AllowComments: true}, {AllowComments: true, EmitWhitespace: true}} {
+		l := NewWithOptions(input, opts)
+		var tokens []token.Token
+		for tok := l.NextToken(); ; tok = l.NextToken() {
+			if tok.Type != token.WHITESPACE {
+				tokens = append(tokens, tok)
+			}
+			if tok.Type == token.EOF {
+				break
+			}
+		}
+		if len(tokens) != len(expected) {
+			t.Fatalf("len(tokens) not %d, got %d: %v", len(expected), len(tokens), tokens)
+		}
+		for i, e := range expected {
+			if tokens[i].Type != e.expectedType || tokens[i].Literal != e.expectedLiteral {
+				t.Errorf("tokens[%d] wrong. expected=%s %q, got=%s %q", i, e.expectedType, e.expectedLiteral, tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	type Expected struct {
+		expectedType    token.Type
+		expectedLiteral string
+		line, column    int
+	}
+
+	input := "a >= 'x'\n  IS\tNOT NULL\r\n\t`你好` <> 12.5e3\r\rf(-- c"
+	expected := []Expected{
+		{token.IDENT, "a", 1, 1},
+		{token.GT_EQ, ">=", 1, 3},
+		{token.STRING, "'x'", 1, 6},
+		{token.IS_NOT, "IS NOT", 2, 3},
+		{token.NULL, "NULL", 2, 10},
+		{token.BACK_QUOTE_IDENT, "`你好`", 3, 2},
+		{token.NOT_EQ, "<>", 3, 7},
+		{token.NUMBER, "12.5e3", 3, 10},
+		{token.IDENT, "f", 5, 1},
+		{token.LPAREN, "(", 5, 2},
+		{token.ILLEGAL, `not support SQL comment: "-- c"`, 5, 3},
+		{token.EOF, "", 5, 7},
+	}
+
+	l := New(input)
+	for i, e := range expected {
+		tok := l.NextToken()
+		if tok.Type != e.expectedType || tok.Literal != e.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong. expected=%s %q, got=%s %q", i, e.expectedType, e.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if tok.Line != e.line || tok.Column != e.column {
+			t.Errorf("tests[%d] - %q position wrong. expected=%d:%d, got=%d:%d", i, tok.Literal, e.line, e.column, tok.Line, tok.Column)
+		}
+	}
+
+	l = New("a +\n ;")
+	l.NextToken()
+	l.NextToken()
+	if err := l.NextToken().IsError(); err == nil || err.Error() != "2:2: not support token `;`" {
+		t.Errorf("err wrong, got %v", err)
+	}
+}
+
+func TestNewBytes(t *testing.T) {
+	inputs := []string{
+		"名字 = '你好' AND `列` <> \"ü\" || '😀'",
+		"a >= 'x'\n  IS\tNOT NULL\r\n\t`你好` <> 12.5e3",
+		"f(x, 'é') -- 注释",
+		"'\xff' + \xe4\xbd",
+		"",
+	}
+	for _, input := range inputs {
+		expected := New(input)
+		l := NewBytes([]byte(input))
+		for i := 0; ; i++ {
+			e, tok := expected.NextToken(), l.NextToken()
+			if tok != e {
+				t.Errorf("%q: tests[%d] - token wrong. expected=%+v, got=%+v", input, i, e, tok)
+				break
+			}
+			if tok.Type == token.EOF {
+				break
+			}
+		}
+	}
+}
+
 func TestExpressions(t *testing.T) {
 	type TestCase struct {
 		input   string
@@ -535,3 +1114,95 @@ func BenchmarkLexerParse(b *testing.B) {
 		}
 	}
 }
+
+func TestMergedTokens(t *testing.T) {
+	input := `a IS NOT NULL AND b NOT IN (1) OR c NOT
+	BETWEEN 1 AND 2 OR d not like 'x' OR NOT e OR NOT (f) OR NOT NULL
+	IS NULL OR NOT NOT IN g IS IS NOT NOT`
+	expected := ExpectedLiterals{
+		{token.IDENT, "a"},
+		{token.IS_NOT, "IS NOT"},
+		{token.NULL, "NULL"},
+		{token.AND, "AND"},
+		{token.IDENT, "b"},
+		{token.NOT_IN, "NOT IN"},
+		{token.LPAREN, "("},
+		{token.NUMBER, "1"},
+		{token.RPAREN, ")"},
+		{token.OR, "OR"},
+		{token.IDENT, "c"},
+		{token.NOT_BETWEEN, "NOT BETWEEN"},
+		{token.NUMBER, "1"},
+		{token.AND, "AND"},
+		{token.NUMBER, "2"},
+		{token.OR, "OR"},
+		{token.IDENT, "d"},
+		{token.NOT_LIKE, "NOT LIKE"},
+		{token.STRING, "'x'"},
+		{token.OR, "OR"},
+		{token.NOT, "NOT"},
+		{token.IDENT, "e"},
+		{token.OR, "OR"},
+		{token.NOT, "NOT"},
+		{token.LPAREN, "("},
+		{token.IDENT, "f"},
+		{token.RPAREN, ")"},
+		{token.OR, "OR"},
+		{token.NOT, "NOT"},
+		{token.NULL, "NULL"},
+		{token.IS, "IS"},
+		{token.NULL, "NULL"},
+		{token.OR, "OR"},
+		{token.NOT, "NOT"},
+		{token.NOT_IN, "NOT IN"},
+		{token.IDENT, "g"},
+		{token.IS, "IS"},
+		{token.IS_NOT, "IS NOT"},
+		{token.NOT, "NOT"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestMergedTokens", l)
+}
+
+func TestNotMergeBoundaries(t *testing.T) {
+	// NOT only merges with the whole keywords IN, BETWEEN and LIKE
+	input := `NOT INTERVAL NOT in_stock NOT between_x NOT likes NOT EXISTS NOT AND NOT f() NOT`
+	expected := ExpectedLiterals{
+		{token.NOT, "NOT"},
+		{token.INTERVAL, "INTERVAL"},
+		{token.NOT, "NOT"},
+		{token.IDENT, "in_stock"},
+		{token.NOT, "NOT"},
+		{token.IDENT, "between_x"},
+		{token.NOT, "NOT"},
+		{token.IDENT, "likes"},
+		{token.NOT, "NOT"},
+		{token.EXISTS, "EXISTS"},
+		{token.NOT, "NOT"},
+		{token.AND, "AND"},
+		{token.NOT, "NOT"},
+		{token.IDENT, "f"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.NOT, "NOT"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	expected.testAll(t, "TestNotMergeBoundaries", l)
+}
+
+func BenchmarkNextTokenKeywords(b *testing.B) {
+	input := strings.Repeat(`a IS NOT NULL AND b NOT IN (1, 2) OR c NOT BETWEEN x AND y
+	AND d NOT LIKE 'x%' OR NOT e AND f IS NULL OR CASE WHEN g THEN h ELSE i END = j `, 20)
+
+	for i := 0; i < b.N; i++ {
+		if err := testBenchmark(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
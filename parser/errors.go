@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chenjunwen186/sqlexpr/token"
+)
+
+// Error is a parse error at the 1-based line and column of the offending token
+type Error struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FormatError renders err with the offending line of the input
+// and a `^` under the offending column, like
+//
+//	2:3: no prefix parse function for ")" found
+//	  )
+//	  ^
+//
+// The position is taken from a `*Error` or a `*token.Error` of the lexer,
+// other errors are returned as is.
+// Columns count runes, so the caret may be off under double-width characters.
+func FormatError(input string, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var line, column int
+	var parseErr *Error
+	var tokenErr *token.Error
+	if errors.As(err, &parseErr) {
+		line, column = parseErr.Line, parseErr.Column
+	} else if errors.As(err, &tokenErr) {
+		line, column = tokenErr.Line, tokenErr.Column
+	}
+
+	lines := splitLines(input)
+	if line < 1 || line > len(lines) || column < 1 {
+		return err.Error()
+	}
+
+	// Keep tabs so the caret lines up with the source however tabs are displayed
+	src := []rune(lines[line-1])
+	var caret strings.Builder
+	for i := 0; i < column-1 && i < len(src); i++ {
+		if src[i] == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+
+	return err.Error() + "\n" + string(src) + "\n" + caret.String()
+}
+
+// Splits on `\n`, `\r\n` or a lone `\r`, the same line endings as the lexer
+func splitLines(input string) []string {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	input = strings.ReplaceAll(input, "\r", "\n")
+
+	return strings.Split(input, "\n")
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chenjunwen186/sqlexpr/lexer"
+)
+
+func TestFormatError(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+	}
+
+	inputs := []TestCase{
+		{
+			"a = 1\n  AND b +\n  )",
+			"3:3: no prefix parse function for \")\" found\n  )\n  ^",
+		},
+		{
+			"a = 1\r\n\tAND b; c",
+			"2:7: not support token `;`\n\tAND b; c\n\t     ^",
+		},
+		{
+			"f(x,\r\t'unclosed)",
+			"2:2: unexpected EOF: 'unclosed)\n\t'unclosed)\n\t^",
+		},
+		{
+			"'café' + )",
+			"1:10: no prefix parse function for \")\" found\n'café' + )\n         ^",
+		},
+		{
+			"p1 IMMEDIATELY p2",
+			"1:16: expected PRECEDES or SUCCEEDS after IMMEDIATELY, got \"p2\" instead\np1 IMMEDIATELY p2\n               ^",
+		},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{PeriodPredicates: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if s := FormatError(input.input, err); s != input.expected {
+			t.Errorf("FormatError(%q) wrong.\nexpected:\n%s\ngot:\n%s", input.input, input.expected, s)
+		}
+	}
+
+	// Errors without a position are returned as is
+	if s := FormatError("a", errors.New("oops")); s != "oops" {
+		t.Errorf("FormatError() not %q, got %q", "oops", s)
+	}
+	if s := FormatError("a", &Error{Line: 3, Column: 1, Err: errors.New("oops")}); s != "3:1: oops" {
+		t.Errorf("FormatError() not %q, got %q", "3:1: oops", s)
+	}
+	if s := FormatError("a", nil); s != "" {
+		t.Errorf("FormatError() not empty, got %q", s)
+	}
+
+	_, err := parseExpressionWithError(t, "a +\n  )")
+	var parseErr *Error
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Column != 3 {
+		t.Errorf("err not a *Error at 2:3, got %v", err)
+	}
+}
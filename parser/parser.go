@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/chenjunwen186/sqlexpr/ast"
 	"github.com/chenjunwen186/sqlexpr/lexer"
@@ -12,18 +16,27 @@ import (
 const (
 	_ int = iota
 	LOWEST
-	AS   // AS
-	COND // OR or AND
-	IN   // IN
+	AS      // AS
+	TERNARY // a ? b : c
+	COND    // OR or AND
+	NOT     // NOT x, binds looser than every operator except AND and OR
+	IN      // IN
 	// BETWEEN     // BETWEEN
-	NOT         // NOT
 	EQUALS      // = <> <=>
 	LESSGREATER // > or < <= >=
+	OTHER       // OPERATOR(schema.op), JSON operators like #> and #>> or containment like @>
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << or >>
 	SUM         // + or -
 	PRODUCT     // * or /
 	MOD         // %
 	IS          // IS
+	COLLATE     // COLLATE
 	PREFIX      // -X or +X or ~X or DISTINCT
+	CAST        // X::type
+	JSONACCESS  // -> or ->>
 	CALL
 	HIGHEST
 )
@@ -33,6 +46,9 @@ const PASS = LOWEST
 type (
 	prefixParseFn func() (ast.Expression, error)
 	infixParseFn  func(ast.Expression) (ast.Expression, error)
+	// Parses the arguments of a special-cased function call like `POSITION(a IN b)`,
+	// called with the current token at `(`
+	callParseFn func(fn ast.Expression) (ast.Expression, error)
 )
 
 // Each token precedence
@@ -44,6 +60,8 @@ var precedences = map[token.Type]int{
 	token.THEN:   LOWEST,
 	token.ELSE:   LOWEST,
 	token.END:    LOWEST,
+	token.EQ_GT:  LOWEST,
+	token.AS:     AS,
 
 	token.IN:          IN,
 	token.NOT_IN:      IN,
@@ -54,6 +72,9 @@ var precedences = map[token.Type]int{
 
 	token.IS:     IS,
 	token.IS_NOT: IS,
+	token.NOT:    IS, // only `x NOT NULL`, see parseNotNull
+
+	token.COLLATE: COLLATE,
 
 	token.EQ:      EQUALS,
 	token.BANG_EQ: EQUALS,
@@ -65,17 +86,154 @@ var precedences = map[token.Type]int{
 	token.GT:       LESSGREATER,
 	token.GT_EQ:    LESSGREATER,
 
+	token.PIPE:     BITOR,
+	token.XOR:      BITXOR,
+	token.AMP:      BITAND,
+	token.LT2:      SHIFT,
+	token.RT2:      SHIFT,
 	token.PLUS:     SUM,
 	token.MINUS:    SUM,
 	token.ASTERISK: PRODUCT,
 	token.SLASH:    PRODUCT,
 	token.MOD:      MOD,
-	token.TILDE:    PREFIX,
+	token.TILDE:    PREFIX, // prefix-only, a binary `~` is rejected by parseUnexpectedTilde
+	token.DISTINCT: PREFIX, // prefix-only, a binary `DISTINCT` is rejected by parseUnexpectedDistinct
 
 	token.AND: COND,
 	token.OR:  COND,
 
-	token.LPAREN: CALL,
+	token.COLON2: CAST,
+
+	token.LPAREN:   CALL,
+	token.LBRACKET: CALL,
+	token.PERIOD:   CALL,
+
+	token.OPERATOR: OTHER,
+
+	token.PRT:      JSONACCESS,
+	token.PRT2:     JSONACCESS,
+	token.HASH_GT:  OTHER,
+	token.HASH_GT2: OTHER,
+
+	// Containment operators bind like the Postgres "any other operator",
+	// tighter than comparisons and left-associative among themselves,
+	// `a @> b = c` is `(a @> b) = c` and `a && b @> c` is `(a && b) @> c`
+	token.AT_GT: OTHER,
+	token.LT_AT: OTHER,
+	token.AMP2:  OTHER,
+
+	// Only infix operators with the option `JSONBOperators`
+	token.QUESTION:      OTHER,
+	token.QUESTION_PIPE: OTHER,
+	token.QUESTION_AMP:  OTHER,
+}
+
+// Tokens which only end an expression inside the constructs expecting them,
+// like FROM of `TRIM(chars FROM s)` or the `]` of `a[1]`,
+// elsewhere they are an error after an expression.
+// The constructs enable them with `stopAt` while parsing their operands.
+var contextTokens = map[token.Type]string{
+	token.FROM:    "TRIM, SUBSTRING or OVERLAY",
+	token.FOR:     "SUBSTRING or OVERLAY",
+	token.PLACING: "OVERLAY",
+	token.SIMILAR: "SUBSTRING",
+	token.ESCAPE:  "LIKE or SUBSTRING",
+	token.ORDER:   "aggregate call arguments",
+	token.ASC:     "ORDER BY",
+	token.DESC:    "ORDER BY",
+
+	token.RBRACKET: "an array or subscript",
+
+	// Time units end the quantity of `INTERVAL 3 MONTH`
+	token.SECOND:  "INTERVAL",
+	token.MINUTE:  "INTERVAL",
+	token.HOUR:    "INTERVAL",
+	token.DAY:     "INTERVAL",
+	token.WEEK:    "INTERVAL",
+	token.MONTH:   "INTERVAL",
+	token.QUARTER: "INTERVAL",
+	token.YEAR:    "INTERVAL",
+}
+
+// The time units ending the quantity of INTERVAL, see `token.Type.IsTimeUnit`
+var timeUnits = []token.Type{token.SECOND, token.MINUTE, token.HOUR, token.DAY, token.WEEK, token.MONTH, token.QUARTER, token.YEAR}
+
+type Options struct {
+	// Upper-case the literal of keyword tokens as they are consumed,
+	// so nodes carry `AND` or `TRUE` instead of the input casing like `aNd`
+	NormalizeKeywordCase bool
+
+	// The maximum number of call arguments or tuple elements, 0 means unlimited
+	MaxListElements int
+
+	// Parse the Postgres `a OPERATOR(schema.op) b` syntax.
+	// `OPERATOR` is not a keyword, it is only recognized with this option on,
+	// and can still be used as an identifier in operand position.
+	PostgresOperators bool
+
+	// Parse the identifiers `Infinity` and `NaN` (case-insensitive)
+	// as special float `ast.NumberLiteral`s, `-Infinity` is the negation of `Infinity`.
+	SpecialFloatLiterals bool
+
+	// Reject number literals whose absolute value is greater than this, 0 means unlimited.
+	// It guards against inputs like `1e308`, `Infinity` is always rejected when set.
+	MaxNumberMagnitude float64
+
+	// Parse `ROLLUP(...)`, `CUBE(...)` and `GROUPING SETS(...)` of grouping contexts
+	// as `ast.GroupingConstruct`s, they are generic calls or errors by default.
+	// `GROUPING(col)` is always a generic call.
+	GroupingConstructs bool
+
+	// Parse the Postgres jsonb operators `?` (key exists), `?|` (any key exists)
+	// and `?&` (all keys exist) as infix expressions, like `data ? 'key'`.
+	// A binary `?` conflicts with the `?` of a ternary `a ? b : c`,
+	// with this option on `?` is always the key-exists operator.
+	JSONBOperators bool
+
+	// Parse a chain of unquoted identifiers joined by `.` without whitespace,
+	// like `db.t.col`, as a single `ast.Identifier` whose value is the dotted name.
+	// They are `ast.QualifiedIdentifier`s by default, with this option on
+	// a `.` separated by whitespace is an error.
+	DottedIdentifiersAsName bool
+
+	// Parse the column constraint `x NOT NULL` as `x IS NOT NULL`,
+	// it is rejected with an error suggesting `IS NOT NULL` by default.
+	NotNullAsIsNotNull bool
+
+	// Parse the XML predicate `x IS [NOT] DOCUMENT`.
+	// `DOCUMENT` is not a keyword, it is only recognized right after `IS` or `IS NOT`
+	// with this option on, so a column named `document` can still be used elsewhere.
+	XMLPredicates bool
+
+	// Parse ClickHouse lambdas like `x -> x > 1` or `(x, y) -> x + y`,
+	// as in `arrayFilter(x -> x > 1, arr)`.
+	// `->` is the JSON access operator by default, with this option on it is always a lambda.
+	Lambdas bool
+
+	// Parse the SQL:2011 period predicates `p1 CONTAINS p2`, `p1 PRECEDES p2`, `p1 SUCCEEDS p2`,
+	// `p1 IMMEDIATELY PRECEDES p2` and `p1 IMMEDIATELY SUCCEEDS p2` as infix expressions.
+	// Like `DOCUMENT` the words are not keywords, they are only recognized after an operand
+	// with this option on, so `contains(a, b)` can still be called as a function.
+	PeriodPredicates bool
+
+	// Parse the keyword `DEFAULT` as an `ast.DefaultLiteral`, a value in assignment-like
+	// contexts like `col = DEFAULT` or the tuple `(1, DEFAULT)`. It is denied by default.
+	AllowDefaultKeyword bool
+
+	// Parse an identifier right after an expression as its alias, like `COUNT(*) c`
+	// for `COUNT(*) AS c`. Period predicates take precedence with `PeriodPredicates` on.
+	ImplicitAliases bool
+
+	// Parse the Oracle nested table predicate `x IS [NOT] A SET`.
+	// Like `DOCUMENT`, `A` is only recognized right after `IS` or `IS NOT` with this option on,
+	// and `SET` is only allowed after it.
+	OracleSetPredicates bool
+
+	// Parse ordered-set aggregates like `PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY x)`
+	// and aggregate ORDER BY like `ARRAY_AGG(x ORDER BY y DESC)`.
+	// `GROUP`, `ORDER`, `BY`, `ASC` and `DESC` are denied keywords by default,
+	// `WITHIN` is a plain identifier, and they are only allowed as keywords with this option on.
+	OrderedSetAggregates bool
 }
 
 type Parser struct {
@@ -85,10 +243,29 @@ type Parser struct {
 
 	prefixParseFns map[token.Type]prefixParseFn
 	infixParseFns  map[token.Type]infixParseFn
+	callParseFns   map[string]callParseFn
+
+	operatorAliases map[token.Type]token.Type
+
+	opts Options
+
+	// The number of `?` of ternaries waiting for their `:`
+	openTernaries int
+
+	// The number of open constructs expecting each of the contextTokens
+	stops map[token.Type]int
+
+	// The comments right before the current and the peek token,
+	// only lexed with the lexer option `AllowComments`
+	curComments, peekComments []string
 }
 
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l}
+	return NewWithOptions(l, Options{})
+}
+
+func NewWithOptions(l *lexer.Lexer, opts Options) *Parser {
+	p := &Parser{l: l, opts: opts, stops: make(map[token.Type]int)}
 	p.nextToken()
 	p.nextToken()
 
@@ -98,24 +275,35 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.TRUE, p.parseBooleanLiteral)
 	p.registerPrefix(token.FALSE, p.parseBooleanLiteral)
 	p.registerPrefix(token.NULL, p.parseNullLiteral)
+	p.registerPrefix(token.ASTERISK, p.parseWildcard)
+	p.registerPrefix(token.DEFAULT, p.parseDefaultLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.HEX_STRING, p.parseStringLiteral)
+	p.registerPrefix(token.BIT_STRING, p.parseStringLiteral)
 	p.registerPrefix(token.NUMBER, p.parseNumberLiteral)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.PLUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(token.LPAREN, p.parseGroupedOrTupleExpression)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.DISTINCT, p.parsePrefixExpression)
+	p.registerPrefix(token.NOT, p.parseNotExpression)
+	p.registerPrefix(token.OPERATOR, p.parseKeywordAsIdentifier)
 	p.registerPrefix(token.CASE, p.parseCaseWhenExpression)
+	p.registerPrefix(token.EXISTS, p.parseExistsExpression)
+	p.registerPrefix(token.INTERVAL, p.parseIntervalExpression)
+	p.registerPrefix(token.MOD, p.parseMissingLeftOperand)
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
-	// p.registerInfix(token.AS, p.parseInfixExpression)
-	p.registerInfix(token.IN, p.parseInfixExpression)
-	p.registerInfix(token.NOT_IN, p.parseInfixExpression)
+	p.registerInfix(token.AS, p.parseAliasExpression)
+	p.registerInfix(token.IN, p.parseInExpression)
+	p.registerInfix(token.NOT_IN, p.parseInExpression)
 	p.registerInfix(token.BETWEEN, p.parseBetweenExpression)
 	p.registerInfix(token.NOT_BETWEEN, p.parseNotBetweenExpression)
-	p.registerInfix(token.IS, p.parseInfixExpression)
-	p.registerInfix(token.IS_NOT, p.parseInfixExpression)
-	p.registerInfix(token.LIKE, p.parseInfixExpression)
-	p.registerInfix(token.NOT_LIKE, p.parseInfixExpression)
+	p.registerInfix(token.IS, p.parseIsExpression)
+	p.registerInfix(token.IS_NOT, p.parseIsExpression)
+	p.registerInfix(token.LIKE, p.parseLikeExpression)
+	p.registerInfix(token.NOT_LIKE, p.parseLikeExpression)
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -123,6 +311,11 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.MOD, p.parseInfixExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.XOR, p.parseInfixExpression)
+	p.registerInfix(token.AMP, p.parseInfixExpression)
+	p.registerInfix(token.LT2, p.parseInfixExpression)
+	p.registerInfix(token.RT2, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.BANG_EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
@@ -132,6 +325,54 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	if !opts.DottedIdentifiersAsName {
+		p.registerInfix(token.PERIOD, p.parseQualifiedIdentifier)
+	}
+	p.registerInfix(token.COLON2, p.parseDoubleColonCast)
+	p.registerInfix(token.COLLATE, p.parseCollateExpression)
+	p.registerInfix(token.TILDE, p.parseUnexpectedTilde)
+	p.registerInfix(token.DISTINCT, p.parseUnexpectedDistinct)
+	p.registerInfix(token.NOT, p.parseNotNull)
+	p.registerInfix(token.OPERATOR, p.parseCustomOperatorExpression)
+	if opts.Lambdas {
+		p.registerInfix(token.PRT, p.parseLambdaExpression)
+	} else {
+		p.registerInfix(token.PRT, p.parseJSONAccessExpression)
+	}
+	p.registerInfix(token.PRT2, p.parseJSONAccessExpression)
+	p.registerInfix(token.HASH_GT, p.parseInfixExpression)
+	p.registerInfix(token.HASH_GT2, p.parseInfixExpression)
+	p.registerInfix(token.AT_GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_AT, p.parseInfixExpression)
+	p.registerInfix(token.AMP2, p.parseInfixExpression)
+	if opts.JSONBOperators {
+		p.registerInfix(token.QUESTION, p.parseInfixExpression)
+		p.registerInfix(token.QUESTION_PIPE, p.parseInfixExpression)
+		p.registerInfix(token.QUESTION_AMP, p.parseInfixExpression)
+	} else {
+		p.registerInfix(token.QUESTION, p.parseTernaryExpression)
+	}
+	if opts.PeriodPredicates {
+		p.registerInfix(token.IDENT, p.parsePeriodPredicate)
+	}
+	if opts.ImplicitAliases {
+		p.registerInfix(token.IDENT, p.parseImplicitAlias)
+		p.registerInfix(token.BACK_QUOTE_IDENT, p.parseImplicitAlias)
+		p.registerInfix(token.DOUBLE_QUOTE_IDENT, p.parseImplicitAlias)
+	}
+
+	p.callParseFns = make(map[string]callParseFn)
+	p.registerCall("POSITION", p.parsePositionExpression)
+	p.registerCall("TRIM", p.parseTrimExpression)
+	p.registerCall("SUBSTRING", p.parseSubstringExpression)
+	p.registerCall("OVERLAY", p.parseOverlayExpression)
+	p.registerCall("EXTRACT", p.parseExtractExpression)
+	p.registerCall("CAST", p.parseCastExpression)
+	if opts.GroupingConstructs {
+		p.registerCall("ROLLUP", p.parseGroupingConstruct)
+		p.registerCall("CUBE", p.parseGroupingConstruct)
+	}
 
 	return p
 }
@@ -141,13 +382,80 @@ func (p *Parser) ParseExpression() (ast.Expression, error) {
 		return nil, nil
 	}
 
-	return p.parseExpression(LOWEST)
+	expr, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	// CASE keywords stop an expression at LOWEST precedence,
+	// so one left over here is not matched by any CASE
+	switch p.peekToken.Type {
+	case token.WHEN, token.THEN, token.ELSE, token.END:
+		return nil, errorAt(p.peekToken, "unexpected %s outside of CASE", p.peekToken.Type)
+	case token.EQ_GT:
+		return nil, errorAt(p.peekToken, "unexpected %s outside of function call arguments", p.peekToken.Type)
+	}
+
+	return expr, nil
+}
+
+// ParseExpressionList parses the whole input as comma-separated expressions,
+// like the projection `a, b AS x, COUNT(*)`, a single expression is a one-element list.
+// Unlike `ParseExpression`, tokens left after the last expression are an error.
+func (p *Parser) ParseExpressionList() ([]ast.Expression, error) {
+	if p.l.Len() == 0 {
+		return nil, nil
+	}
+
+	var list []ast.Expression
+	for {
+		expr, err := p.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, expr)
+		if p.opts.MaxListElements > 0 && len(list) > p.opts.MaxListElements {
+			return nil, errorAt(p.curToken, "too many list elements: the limit is %d", p.opts.MaxListElements)
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+		if p.peekTokenIs(token.EOF) {
+			return nil, errorAt(p.curToken, "unexpected trailing %s", token.COMMA)
+		}
+		p.nextToken()
+	}
+
+	if !p.peekTokenIs(token.EOF) {
+		return nil, errorAt(p.peekToken, "unexpected %s after the expression", p.peekToken.Literal)
+	}
+
+	return list, nil
+}
+
+// RemainingTokens consumes and returns the tokens after the parsed expression, EOF excluded,
+// like `, c` after `ParseExpression` stops at the comma of `a + b , c`.
+// The `Start` of the first token is the offset where parsing stopped.
+// Tokens are read the same way as while parsing, so whitespace is skipped.
+func (p *Parser) RemainingTokens() []token.Token {
+	var tokens []token.Token
+	for !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+		tokens = append(tokens, p.curToken)
+	}
+
+	return tokens
 }
 
 func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 	prefix := p.prefixParseFns[p.curToken.Type]
+	if err := p.curToken.IsError(); prefix == nil && err != nil {
+		return nil, err
+	}
 	if prefix == nil {
-		return nil, fmt.Errorf("no prefix parse function for %q found", p.curToken.Type)
+		return nil, errorAt(p.curToken, "no prefix parse function for %q found", p.curToken.Type)
 	}
 
 	leftExp, err := prefix()
@@ -155,6 +463,12 @@ func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 		return nil, err
 	}
 
+	return p.parseInfixExpressions(leftExp, precedence)
+}
+
+// Continues parsing infix operators binding tighter than `precedence`
+// onto an already parsed left expression
+func (p *Parser) parseInfixExpressions(leftExp ast.Expression, precedence int) (ast.Expression, error) {
 	for {
 		peekPrecedence, err := p.peekPrecedence()
 		if err != nil {
@@ -166,7 +480,7 @@ func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
-			return nil, fmt.Errorf("no infix parse function for %s found", p.peekToken.Type)
+			return nil, errorAt(p.peekToken, "no infix parse function for %s found", p.peekToken.Type)
 		}
 		p.nextToken()
 		leftExp, err = infix(leftExp)
@@ -180,7 +494,85 @@ func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
+	p.curComments = p.peekComments
+	p.peekComments = nil
 	p.peekToken = p.l.NextToken()
+	for p.peekToken.Type == token.WHITESPACE || p.peekToken.Type == token.COMMENT {
+		if p.peekToken.Type == token.COMMENT {
+			p.peekComments = append(p.peekComments, p.peekToken.Literal)
+		}
+		p.peekToken = p.l.NextToken()
+	}
+	if p.peekToken.Type == token.IDENT {
+		p.peekToken.Type = p.contextualKeyword(p.peekToken.Literal)
+	}
+	if p.opts.AllowDefaultKeyword {
+		p.peekToken, _ = token.AllowDeniedKeyword(p.peekToken, token.DEFAULT)
+	}
+	if p.opts.OrderedSetAggregates {
+		p.peekToken = orderedSetAggregateKeyword(p.peekToken)
+	}
+	if canonical, ok := p.operatorAliases[p.peekToken.Type]; ok {
+		p.peekToken.Type = canonical
+	}
+	if p.opts.NormalizeKeywordCase && p.peekToken.Type.IsKeyword() {
+		p.peekToken.Literal = strings.ToUpper(p.peekToken.Literal)
+	}
+}
+
+// Identifiers which are keywords depending on the options
+func (p *Parser) contextualKeyword(ident string) token.Type {
+	switch strings.ToUpper(ident) {
+	case token.OPERATOR:
+		if p.opts.PostgresOperators {
+			return token.OPERATOR
+		}
+	case "INFINITY", "NAN":
+		if p.opts.SpecialFloatLiterals {
+			return token.NUMBER
+		}
+	}
+
+	return token.IDENT
+}
+
+// Converts the denied keywords and the identifier `WITHIN` of `WITHIN GROUP (ORDER BY x DESC)`
+// to keyword tokens, keeping the input casing
+func orderedSetAggregateKeyword(tok token.Token) token.Token {
+	word := tok.Literal
+	if _, ok := token.DeniedKeyword(tok); ok {
+		word = tok.Denied
+	} else if tok.Type != token.IDENT {
+		return tok
+	}
+
+	keyword, ok := token.LookupOrderedSetAggregateKeyword(word)
+	if !ok {
+		return tok
+	}
+	tok.Type, tok.Literal, tok.Denied = keyword.Type, keyword.Literal, ""
+
+	return tok
+}
+
+// RegisterOperatorAlias makes the parser treat the operator token alias as canonical,
+// so nodes carry the canonical type, like `token.NOT_EQ` for `!=`
+// with `RegisterOperatorAlias(token.BANG_EQ, token.NOT_EQ)`,
+// while the token literal keeps the input spelling.
+// `String()` renders the canonical operator, `ast.Render` can render the input spelling.
+// Only single tokens can be aliased, `&&` is two `&` tokens.
+func (p *Parser) RegisterOperatorAlias(alias, canonical token.Type) {
+	if p.operatorAliases == nil {
+		p.operatorAliases = make(map[token.Type]token.Type)
+	}
+	p.operatorAliases[alias] = canonical
+
+	// The first tokens are already read by the constructor
+	for _, tok := range []*token.Token{&p.curToken, &p.peekToken} {
+		if tok.Type == alias {
+			tok.Type = canonical
+		}
+	}
 }
 
 func (p *Parser) registerPrefix(tokenType token.Type, fn prefixParseFn) {
@@ -191,12 +583,17 @@ func (p *Parser) registerInfix(tokenType token.Type, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn
 }
 
+// The name is matched case-insensitively
+func (p *Parser) registerCall(name string, fn callParseFn) {
+	p.callParseFns[strings.ToUpper(name)] = fn
+}
+
 func (p *Parser) expectPeek(t token.Type) error {
 	if p.peekToken.Type == t {
 		p.nextToken()
 		return nil
 	}
-	return fmt.Errorf("expected next token to be %q, got %q instead", t, p.curToken.Type)
+	return errorAt(p.peekToken, "expected next token to be %q, got %q instead", t, p.peekToken.Type)
 }
 
 func (p *Parser) curTokenIs(t token.Type) bool {
@@ -207,13 +604,113 @@ func (p *Parser) peekTokenIs(t token.Type) bool {
 	return p.peekToken.Type == t
 }
 
+// Reports whether the next token is the keyword t, converting an identifier
+// spelling the contextual keyword t, like `for` of `SUBSTRING(s for 2)`, to it
+func (p *Parser) peekKeyword(t token.Type) bool {
+	if p.peekToken.Type == token.IDENT {
+		if typ, ok := token.LookupContextualKeyword(p.peekToken.Literal); ok && typ == t {
+			p.peekToken.Type = typ
+			if p.opts.NormalizeKeywordCase {
+				p.peekToken.Literal = strings.ToUpper(p.peekToken.Literal)
+			}
+		}
+	}
+
+	return p.peekToken.Type == t
+}
+
+var errNoPrecedence = errors.New("no precedence found")
+
 // Looks up the precedence of the next token
 func (p *Parser) peekPrecedence() (int, error) {
+	// With the Lambdas option `->` takes the whole left operand as the parameters
+	if p.peekToken.Type == token.PRT && p.opts.Lambdas {
+		return OTHER, nil
+	}
+	// Without the JSONBOperators option `?` starts a ternary `a ? b : c`
+	if p.peekToken.Type == token.QUESTION && !p.opts.JSONBOperators {
+		return TERNARY, nil
+	}
+	// The contextual keywords after an operand, they are aliases or unexpected elsewhere
+	if typ, ok := token.LookupContextualKeyword(p.peekToken.Literal); ok && p.stops[typ] > 0 && p.peekKeyword(typ) {
+		return LOWEST, nil
+	}
+	if p.peekKeyword(token.COLLATE) {
+		return COLLATE, nil
+	}
+	if p.opts.PeriodPredicates && isPeriodPredicate(p.peekToken) {
+		return EQUALS, nil
+	}
+	if p.opts.ImplicitAliases && isAliasName(p.peekToken) {
+		return AS, nil
+	}
+	// The `:` of a ternary ends its true branch
+	if p.peekToken.Type == token.COLON && p.openTernaries > 0 {
+		return LOWEST, nil
+	}
+	if where, ok := contextTokens[p.peekToken.Type]; ok {
+		if p.stops[p.peekToken.Type] > 0 {
+			return LOWEST, nil
+		}
+		return 0, errorAt(p.peekToken, "unexpected %s outside of %s", p.peekToken.Literal, where)
+	}
 	if p, ok := precedences[p.peekToken.Type]; ok {
 		return p, nil
 	}
+	// Report the lexer error, like a not supported comment
+	if err := p.peekToken.IsError(); err != nil {
+		return 0, err
+	}
+	// A contextual keyword, like `for` of `a FOR b`, without its construct
+	if typ, ok := token.LookupContextualKeyword(p.peekToken.Literal); ok && p.peekToken.Type == token.IDENT {
+		if where, ok := contextTokens[typ]; ok {
+			return 0, errorAt(p.peekToken, "unexpected %s outside of %s", p.peekToken.Literal, where)
+		}
+	}
+
+	// A statement-level construct after an operand, like `t TABLESAMPLE ...` or `lateral f(x)`
+	for _, tok := range []token.Token{p.peekToken, p.curToken} {
+		if tok.Type == token.IDENT && token.IsStatementKeyword(tok.Literal) {
+			return 0, errorAt(tok, "%s is a statement-level construct not supported in expression mode", strings.ToUpper(tok.Literal))
+		}
+	}
+
+	return 0, errorAt(p.peekToken, "peekPrecedence(): %w for %q, literal: %q", errNoPrecedence, p.peekToken.Type, p.peekToken.Literal)
+}
+
+// Makes the contextTokens types end expressions until the returned function is called
+func (p *Parser) stopAt(types ...token.Type) (release func()) {
+	for _, t := range types {
+		p.stops[t]++
+	}
+
+	return func() {
+		for _, t := range types {
+			p.stops[t]--
+		}
+	}
+}
+
+// Formats an error as an `*Error` at the position of the token, when it is known
+func errorAt(tok token.Token, format string, a ...any) error {
+	err := fmt.Errorf(format, a...)
+	if tok.Line == 0 {
+		return err
+	}
+
+	return &Error{Line: tok.Line, Column: tok.Column, Err: err}
+}
+
+// Wraps err like `errorAt`, at the position of an inner `*Error` instead of tok when it has one,
+// so the position is not repeated in the message
+func wrapErrorAt(tok token.Token, format string, err error) error {
+	var inner *Error
+	if errors.As(err, &inner) {
+		tok.Line, tok.Column = inner.Line, inner.Column
+		err = inner.Err
+	}
 
-	return 0, fmt.Errorf("peekPrecedence(): no precedence found for %q, literal: %q", p.peekToken.Type, p.peekToken.Literal)
+	return errorAt(tok, format, err)
 }
 
 // Looks up the precedence of the current token
@@ -222,7 +719,7 @@ func (p *Parser) curPrecedence() (int, error) {
 		return p, nil
 	}
 
-	return 0, fmt.Errorf("curPrecedence(): no precedence found for %s, literal: %s", p.curToken.Type, p.curToken.Literal)
+	return 0, errorAt(p.curToken, "curPrecedence(): no precedence found for %s, literal: %s", p.curToken.Type, p.curToken.Literal)
 }
 
 func (p *Parser) parsePrefixExpression() (ast.Expression, error) {
@@ -239,6 +736,22 @@ func (p *Parser) parsePrefixExpression() (ast.Expression, error) {
 	return expr, err
 }
 
+// Unlike other prefix operators, the operand of `NOT` extends over comparisons,
+// `NOT a = b` is `(NOT (a = b))` while `NOT a AND b` is `((NOT a) AND b)`
+func (p *Parser) parseNotExpression() (ast.Expression, error) {
+	expr := &ast.PrefixExpression{
+		Token: p.curToken,
+	}
+	p.nextToken()
+	var err error
+	expr.Right, err = p.parseExpression(NOT)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, error) {
 	expr := &ast.InfixExpression{
 		Token: p.curToken,
@@ -262,13 +775,146 @@ func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, erro
 var EOFErr = fmt.Errorf("unexpected EOF error")
 
 func (p *Parser) parseUnexpectedEOF() (ast.Expression, error) {
-	return nil, EOFErr
+	return nil, errorAt(p.curToken, "%w", EOFErr)
+}
+
+func (p *Parser) parseUnexpectedTilde(left ast.Expression) (ast.Expression, error) {
+	return nil, errorAt(p.curToken, "`~` is the prefix bitwise NOT operator and cannot be used between two expressions")
+}
+
+func (p *Parser) parseUnexpectedDistinct(left ast.Expression) (ast.Expression, error) {
+	return nil, errorAt(p.curToken, "`DISTINCT` can only prefix an expression like `COUNT(DISTINCT x)` and cannot be used between two expressions")
+}
+
+// Parses the column constraint `x NOT NULL` found in expression context,
+// a `NOT` after an expression is an error otherwise
+func (p *Parser) parseNotNull(left ast.Expression) (ast.Expression, error) {
+	if p.peekTokenIs(token.EOF) {
+		return nil, errorAt(p.curToken, "`%s NOT` is incomplete, expected IN, BETWEEN or LIKE after NOT", left.String())
+	}
+	if !p.peekTokenIs(token.NULL) {
+		return nil, errorAt(p.curToken, "`NOT` can only prefix an expression like `NOT x` and cannot be used between two expressions")
+	}
+	if !p.opts.NotNullAsIsNotNull {
+		return nil, errorAt(p.curToken, "`%s NOT NULL` is a column constraint, use `%s IS NOT NULL` to test for NULL", left.String(), left.String())
+	}
+
+	tok := p.curToken
+	tok.Type, tok.Literal = token.IS_NOT, "IS NOT"
+	p.nextToken()
+
+	return &ast.IsExpression{Token: tok, Left: left, Negated: true, Predicate: token.NULL, PredicateLiteral: p.curToken.Literal}, nil
+}
+
+// For binary-only operators found where an operand is expected, like `% a`
+func (p *Parser) parseMissingLeftOperand() (ast.Expression, error) {
+	return nil, errorAt(p.curToken, "`%s` is a binary operator and requires an operand before it", p.curToken.Literal)
 }
 
 func (p *Parser) parseIdentifier() (ast.Expression, error) {
+	if p.opts.GroupingConstructs && p.isGroupingSets() {
+		return p.parseGroupingSets()
+	}
+	if p.opts.DottedIdentifiersAsName && p.isAdjacentPeek(token.PERIOD) {
+		return p.parseDottedName()
+	}
+	if strings.EqualFold(p.curToken.Literal, "ARRAY") && p.peekTokenIs(token.LBRACKET) {
+		return p.parseTypedArrayLiteral()
+	}
+
+	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}, nil
+}
+
+// Parses `t.col` or `db.schema.t.col`, the current token is the first `.`.
+// Keywords, including denied ones, are names after a `.`, like `t.year` or `s.table`.
+func (p *Parser) parseQualifiedIdentifier(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.QualifiedIdentifier{Token: p.curToken}
+	switch v := left.(type) {
+	case *ast.Identifier:
+		expr.Parts = []*ast.Identifier{v}
+	case *ast.QualifiedIdentifier:
+		expr.Parts = append(expr.Parts, v.Parts...)
+	default:
+		return nil, errorAt(p.curToken, "expected an identifier before %q, got %s", token.PERIOD, left.String())
+	}
+
+	for {
+		if p.peekTokenIs(token.ASTERISK) {
+			return p.parseQualifiedWildcard(expr)
+		}
+		part, err := p.parseQualifiedPart(expr.Parts)
+		if err != nil {
+			return nil, err
+		}
+		expr.Parts = append(expr.Parts, part)
+
+		if !p.peekTokenIs(token.PERIOD) {
+			return expr, nil
+		}
+		p.nextToken()
+	}
+}
+
+// `t.*`, the current token is the `.` and the next is `*`
+func (p *Parser) parseQualifiedWildcard(name *ast.QualifiedIdentifier) (ast.Expression, error) {
+	if len(name.Parts) != 1 {
+		return nil, errorAt(p.peekToken, "expected a table name before %q, got %q", ".*", name.String())
+	}
+	expr := &ast.QualifiedWildcard{Token: p.curToken, Table: name.Parts[0]}
+	p.nextToken()
+
+	return expr, nil
+}
+
+// The name after a `.`, the current token is the `.`
+func (p *Parser) parseQualifiedPart(prefix []*ast.Identifier) (*ast.Identifier, error) {
+	switch {
+	case p.peekTokenIs(token.IDENT):
+	case p.peekToken.Type.IsKeyword():
+		p.peekToken.Type = token.IDENT
+	default:
+		keyword, ok := token.DeniedKeyword(p.peekToken)
+		if ok {
+			p.peekToken, _ = token.AllowDeniedKeyword(p.peekToken, token.Type(keyword))
+			p.peekToken.Type = token.IDENT
+			break
+		}
+		name := (&ast.QualifiedIdentifier{Parts: prefix}).String()
+		return nil, errorAt(p.peekToken, "expected an identifier after %q, got %q instead", name+token.PERIOD, p.peekToken.Type)
+	}
+	p.nextToken()
+
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}, nil
 }
 
+// Whether the next token has the type and follows the current one without whitespace
+func (p *Parser) isAdjacentPeek(t token.Type) bool {
+	return p.peekTokenIs(t) && p.peekToken.Start == p.curToken.End
+}
+
+// Parses `a.b.c` into one identifier, the current token is `a`
+func (p *Parser) parseDottedName() (ast.Expression, error) {
+	tok := p.curToken
+	for p.isAdjacentPeek(token.PERIOD) {
+		p.nextToken()
+		if !p.isAdjacentPeek(token.IDENT) {
+			return nil, errorAt(p.peekToken, "expected an identifier right after %q, got %q instead", tok.Literal+".", p.peekToken.Type)
+		}
+		p.nextToken()
+		tok.Literal += "." + p.curToken.Literal
+		tok.End = p.curToken.End
+	}
+
+	return &ast.Identifier{Token: tok, Value: tok.Literal}, nil
+}
+
+// For contextual keywords like `OPERATOR` used as an identifier
+func (p *Parser) parseKeywordAsIdentifier() (ast.Expression, error) {
+	tok := p.curToken
+	tok.Type = token.IDENT
+	return &ast.Identifier{Token: tok, Value: tok.Literal}, nil
+}
+
 func (p *Parser) parseBooleanLiteral() (ast.Expression, error) {
 	return &ast.BooleanLiteral{Token: p.curToken}, nil
 }
@@ -277,22 +923,70 @@ func (p *Parser) parseNullLiteral() (ast.Expression, error) {
 	return &ast.NullLiteral{Token: p.curToken}, nil
 }
 
+// A `*` in operand position, like `COUNT(*)`, it is a multiplication after an operand
+func (p *Parser) parseWildcard() (ast.Expression, error) {
+	return &ast.Wildcard{Token: p.curToken}, nil
+}
+
+// DEFAULT, only a token with the option `AllowDefaultKeyword`
+func (p *Parser) parseDefaultLiteral() (ast.Expression, error) {
+	return &ast.DefaultLiteral{Token: p.curToken}, nil
+}
+
 func (p *Parser) parseStringLiteral() (ast.Expression, error) {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}, nil
 }
 
 func (p *Parser) parseNumberLiteral() (ast.Expression, error) {
-	return &ast.NumberLiteral{Token: p.curToken}, nil
+	literal := &ast.NumberLiteral{Token: p.curToken}
+	if p.opts.MaxNumberMagnitude > 0 {
+		if err := p.checkNumberMagnitude(literal); err != nil {
+			return nil, err
+		}
+	}
+
+	return literal, nil
+}
+
+// Checks the value of `ast.NumberLiteral.Value`, `NaN` has no magnitude
+// and literals beyond the float64 range like `1e400` exceed any maximum
+func (p *Parser) checkNumberMagnitude(literal *ast.NumberLiteral) error {
+	value, err := literal.Value()
+	if errors.Is(err, ast.ErrNumberRange) {
+		value = math.Inf(1)
+	} else if err != nil {
+		return err
+	}
+
+	if math.Abs(value) > p.opts.MaxNumberMagnitude {
+		return errorAt(literal.Token, "number literal %s exceeds the maximum magnitude %g", literal.Literal, p.opts.MaxNumberMagnitude)
+	}
+
+	return nil
 }
 
+// CASE [operand] WHEN cond THEN result ... [ELSE result] END
 func (p *Parser) parseCaseWhenExpression() (ast.Expression, error) {
+	tok := p.curToken
+
+	// The simple form `CASE x WHEN 1 THEN ...`
+	var operand ast.Expression
+	if !p.peekTokenIs(token.WHEN) && !p.peekTokenIs(token.END) && !p.peekTokenIs(token.ELSE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+		var err error
+		operand, err = p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !p.peekTokenIs(token.WHEN) {
-		return nil, fmt.Errorf("CASE must have at least one WHEN")
+		return nil, errorAt(p.peekToken, "CASE must have at least one WHEN")
 	}
 
 	var whens []ast.When
 	for p.peekTokenIs(token.WHEN) {
 		p.nextToken()
+		comment := strings.Join(p.curComments, "\n")
 		p.nextToken()
 		cond, err := p.parseExpression(LOWEST)
 		if err != nil {
@@ -309,10 +1003,10 @@ func (p *Parser) parseCaseWhenExpression() (ast.Expression, error) {
 			return nil, err
 		}
 
-		whens = append(whens, ast.When{Cond: cond, Then: then})
+		whens = append(whens, ast.When{Cond: cond, Then: then, Comment: comment})
 	}
 	if len(whens) == 0 {
-		return nil, fmt.Errorf("CASE must have at least one WHEN")
+		return nil, errorAt(p.peekToken, "CASE must have at least one WHEN")
 	}
 
 	var elseExpr ast.Expression
@@ -330,12 +1024,15 @@ func (p *Parser) parseCaseWhenExpression() (ast.Expression, error) {
 		return nil, err
 	}
 
-	return &ast.CaseWhenExpression{Token: p.curToken, Whens: whens, Else: elseExpr}, nil
+	return &ast.CaseWhenExpression{Token: tok, Operand: operand, Whens: whens, Else: elseExpr}, nil
 }
 
 func (p *Parser) parseGroupedOrTupleExpression() (ast.Expression, error) {
 	if p.peekToken.Type == token.RPAREN {
-		return nil, fmt.Errorf("empty `()` is not supported")
+		return nil, errorAt(p.curToken, "empty `()` is not supported")
+	}
+	if err := p.peekSubqueryError(); err != nil {
+		return nil, err
 	}
 
 	p.nextToken()
@@ -350,110 +1047,1322 @@ func (p *Parser) parseGroupedOrTupleExpression() (ast.Expression, error) {
 	}
 
 	if p.peekToken.Type != token.COMMA {
-		return nil, fmt.Errorf("expected `)` or `,`, got %s", p.peekToken.Type)
+		return nil, errorAt(p.peekToken, "expected `)` or `,`, got %s", p.peekToken.Type)
 	}
 
-	var list []ast.Expression
-	list = append(list, expr)
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
-		p.nextToken()
-		v, err := p.parseExpression(LOWEST)
-		if err != nil {
-			return nil, err
-		}
-
-		list = append(list, v)
-	}
-	if err := p.expectPeek(token.RPAREN); err != nil {
+	list, err := p.parseExpressionListFrom(expr, token.RPAREN)
+	if err != nil {
 		return nil, err
 	}
 
 	return &ast.TupleExpression{Expressions: list}, nil
 }
 
-func (p *Parser) parseCallExpression(fn ast.Expression) (ast.Expression, error) {
-	expr := &ast.CallExpression{Token: p.curToken, Fn: fn}
-	var err error
-	expr.Arguments, err = p.parseExpressionList(token.RPAREN)
-	if err != nil {
-		return nil, err
+// Keywords starting a query inside `( )`
+var subqueryKeywords = map[string]bool{
+	"SELECT": true,
+	"WITH":   true,
+	"VALUES": true,
+}
+
+// Returns an error if the peek token starts a subquery,
+// which can't appear in an expression, like `x IN (SELECT ...)`
+func (p *Parser) peekSubqueryError() error {
+	if keyword, ok := token.DeniedKeyword(p.peekToken); ok && subqueryKeywords[keyword] {
+		return errorAt(p.peekToken, "subqueries are not supported in expression-only mode; got keyword %s", keyword)
 	}
 
-	return expr, nil
+	return nil
 }
 
-func (p *Parser) parseExpressionList(end token.Type) ([]ast.Expression, error) {
-	var list []ast.Expression
-	if p.peekTokenIs(end) {
-		p.nextToken()
-		return list, nil
+// EXISTS (subquery), which is never supported but reported clearly
+func (p *Parser) parseExistsExpression() (ast.Expression, error) {
+	if err := p.expectPeek(token.LPAREN); err != nil {
+		return nil, err
 	}
-
-	p.nextToken()
-	v, err := p.parseExpression(LOWEST)
-	if err != nil {
+	if err := p.peekSubqueryError(); err != nil {
 		return nil, err
 	}
 
-	list = append(list, v)
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
-		p.nextToken()
-		v, err := p.parseExpression(LOWEST)
-		if err != nil {
-			return nil, err
-		}
+	return nil, errorAt(p.curToken, "EXISTS requires a subquery, which is not supported in expression-only mode")
+}
+
+func (p *Parser) parseCallExpression(fn ast.Expression) (ast.Expression, error) {
+	if ident, ok := fn.(*ast.Identifier); ok && ident.Token.Type == token.IDENT {
+		if callParse, ok := p.callParseFns[strings.ToUpper(ident.Value)]; ok {
+			return callParse(fn)
+		}
+	}
+
+	return p.parseGenericCallExpression(fn)
+}
+
+func (p *Parser) parseGenericCallExpression(fn ast.Expression) (ast.Expression, error) {
+	expr := &ast.CallExpression{Token: p.curToken, Fn: fn}
+	if err := p.parseCallArguments(expr); err != nil {
+		return nil, err
+	}
+
+	if p.peekTokenIs(token.WITHIN) {
+		p.nextToken()
+		var err error
+		expr.WithinGroup, err = p.parseWithinGroup()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return expr, nil
+}
+
+// WITHIN GROUP (ORDER BY x [ASC | DESC], ...)
+func (p *Parser) parseWithinGroup() ([]*ast.OrderByItem, error) {
+	for _, t := range []token.Type{token.GROUP, token.LPAREN, token.ORDER, token.BY} {
+		if err := p.expectPeek(t); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := p.parseOrderByItems()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Parses a comma-separated list of `expr [ASC | DESC]` after `ORDER BY`
+func (p *Parser) parseOrderByItems() ([]*ast.OrderByItem, error) {
+	var items []*ast.OrderByItem
+	for {
+		p.nextToken()
+		item := &ast.OrderByItem{Token: p.curToken}
+		release := p.stopAt(token.ASC, token.DESC)
+		var err error
+		item.Expr, err = p.parseExpression(LOWEST)
+		release()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekTokenIs(token.ASC) || p.peekTokenIs(token.DESC) {
+			p.nextToken()
+			item.Direction = p.curToken.Type
+		}
+		items = append(items, item)
+
+		if !p.peekTokenIs(token.COMMA) {
+			return items, nil
+		}
+		p.nextToken()
+	}
+}
+
+// Parses the rest of a generic call
+// whose first argument has been parsed by a special-cased call parser
+func (p *Parser) parseCallExpressionFrom(tok token.Token, fn ast.Expression, first ast.Expression) (ast.Expression, error) {
+	first, err := p.parseInfixExpressions(first, LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &ast.CallExpression{Token: tok, Fn: fn}
+	expr.Arguments, err = p.parseExpressionListFrom(first, token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// ROLLUP(...) or CUBE(...)
+func (p *Parser) parseGroupingConstruct(fn ast.Expression) (ast.Expression, error) {
+	ident := fn.(*ast.Identifier)
+	expr := &ast.GroupingConstruct{Token: ident.Token, Kind: strings.ToUpper(ident.Value)}
+	var err error
+	expr.Args, err = p.parseGroupingElements(expr.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) isGroupingSets() bool {
+	return strings.EqualFold(p.curToken.Literal, "GROUPING") &&
+		p.peekTokenIs(token.IDENT) && strings.EqualFold(p.peekToken.Literal, "SETS")
+}
+
+// GROUPING SETS(...)
+func (p *Parser) parseGroupingSets() (ast.Expression, error) {
+	expr := &ast.GroupingConstruct{Token: p.curToken, Kind: ast.GroupingSets}
+	p.nextToken()
+	if err := p.expectPeek(token.LPAREN); err != nil {
+		return nil, err
+	}
+
+	var err error
+	expr.Args, err = p.parseGroupingElements(expr.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// Parses the elements of a grouping construct after its `(`,
+// where the empty grouping set `()` is allowed
+func (p *Parser) parseGroupingElements(kind string) ([]ast.Expression, error) {
+	if p.peekTokenIs(token.RPAREN) {
+		return nil, errorAt(p.peekToken, "%s requires at least one element", kind)
+	}
+
+	var list []ast.Expression
+	for {
+		p.nextToken()
+		if p.curTokenIs(token.LPAREN) && p.peekTokenIs(token.RPAREN) {
+			p.nextToken()
+			list = append(list, &ast.TupleExpression{})
+		} else {
+			v, err := p.parseExpression(LOWEST)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		if p.opts.MaxListElements > 0 && len(list) > p.opts.MaxListElements {
+			return nil, errorAt(p.curToken, "too many list elements: the limit is %d", p.opts.MaxListElements)
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Parses the arguments of a generic call after its `(` up to its `)`,
+// where named arguments `name => value` may follow the positional ones,
+// and an aggregate `ORDER BY` may follow the arguments, like `ARRAY_AGG(x ORDER BY y)`
+func (p *Parser) parseCallArguments(expr *ast.CallExpression) error {
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return nil
+	}
+
+	// The aggregate modifier of `COUNT(DISTINCT x)`, a `DISTINCT` before a later argument is a prefix
+	if p.peekTokenIs(token.DISTINCT) {
+		p.nextToken()
+		expr.Distinct = true
+		if p.peekTokenIs(token.RPAREN) {
+			return errorAt(p.peekToken, "DISTINCT requires an argument, like `COUNT(DISTINCT x)`")
+		}
+	}
+
+	// The first token of each argument, for the position of validation errors
+	var starts []token.Token
+	parseArgument := func() (ast.Expression, error) {
+		starts = append(starts, p.curToken)
+		return p.parseCallArgument()
+	}
+
+	p.nextToken()
+	release := p.stopAt(token.ORDER)
+	first, err := parseArgument()
+	if err != nil {
+		release()
+		return err
+	}
+	args, err := p.parseListElementsFrom(first, parseArgument)
+	release()
+	if err != nil {
+		return err
+	}
+	if err := p.validateNamedArguments(args, starts); err != nil {
+		return err
+	}
+	expr.Arguments = args
+
+	if p.peekTokenIs(token.ORDER) {
+		p.nextToken()
+		if err := p.expectPeek(token.BY); err != nil {
+			return err
+		}
+		expr.OrderBy, err = p.parseOrderByItems()
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
+// Named arguments must follow the positional ones and not repeat
+func (p *Parser) validateNamedArguments(args []ast.Expression, starts []token.Token) error {
+	names := map[string]bool{}
+	for i, arg := range args {
+		named, ok := arg.(*ast.NamedArgument)
+		if !ok {
+			if len(names) > 0 {
+				return errorAt(starts[i], "positional argument %s cannot follow named arguments", arg.String())
+			}
+			continue
+		}
+
+		name := strings.ToUpper(named.Name.Value)
+		if names[name] {
+			return errorAt(named.Name.Token, "duplicate named argument %s", named.Name.Value)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
+
+// Parses `expr` or `name => value` with the current token at its start
+func (p *Parser) parseCallArgument() (ast.Expression, error) {
+	arg, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekTokenIs(token.EQ_GT) {
+		return arg, nil
+	}
+
+	name, ok := arg.(*ast.Identifier)
+	if !ok || name.Token.Type != token.IDENT {
+		return nil, errorAt(p.peekToken, "expected argument name before %s, got %s", token.EQ_GT, arg.String())
+	}
+
+	p.nextToken()
+	named := &ast.NamedArgument{Token: p.curToken, Name: name}
+	p.nextToken()
+	named.Value, err = p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	return named, nil
+}
+
+// ClickHouse-style `[1, 2, 3]`, or the empty `[]`
+func (p *Parser) parseArrayLiteral() (ast.Expression, error) {
+	expr := &ast.ArrayLiteral{Token: p.curToken}
+
+	var err error
+	expr.Elements, err = p.parseExpressionList(token.RBRACKET)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// The Postgres array constructor `ARRAY[1, 2]`, the current token is `ARRAY`
+func (p *Parser) parseTypedArrayLiteral() (ast.Expression, error) {
+	expr := &ast.ArrayLiteral{Token: p.curToken, Typed: true}
+	p.nextToken()
+
+	var err error
+	expr.Elements, err = p.parseExpressionList(token.RBRACKET)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// INTERVAL quantity unit, like `INTERVAL 3 MONTH` or `INTERVAL n + 1 DAY`
+func (p *Parser) parseIntervalExpression() (ast.Expression, error) {
+	expr := &ast.IntervalExpression{Token: p.curToken}
+
+	p.nextToken()
+	release := p.stopAt(timeUnits...)
+	var err error
+	expr.Value, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.peekToken.Type.IsTimeUnit() {
+		got := p.peekToken.Literal
+		if p.peekTokenIs(token.EOF) {
+			got = token.EOF
+		}
+		return nil, errorAt(p.peekToken, "expected a time unit like DAY or MONTH after INTERVAL %s, got %q instead", expr.Value.String(), got)
+	}
+	p.nextToken()
+	expr.Unit = p.curToken.Type
+
+	return expr, nil
+}
+
+// Parses the body of `x -> body` or `(x, y) -> body`, the left side must be the parameters
+func (p *Parser) parseLambdaExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.LambdaExpression{Token: p.curToken}
+
+	params := []ast.Expression{left}
+	if tuple, ok := left.(*ast.TupleExpression); ok {
+		params = tuple.Expressions
+	}
+	names := make(map[string]bool)
+	for _, param := range params {
+		ident, ok := param.(*ast.Identifier)
+		if !ok || ident.Token.Type != token.IDENT {
+			return nil, errorAt(expr.Token, "lambda parameters must be identifiers, got %s", param.String())
+		}
+		name := strings.ToUpper(ident.Value)
+		if names[name] {
+			return nil, errorAt(ident.Token, "duplicate lambda parameter %s", ident.Value)
+		}
+		names[name] = true
+		expr.Params = append(expr.Params, ident)
+	}
+
+	// The body extends as far as possible, like `x -> x > 1 AND x < 5`
+	p.nextToken()
+	var err error
+	expr.Body, err = p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// Parses `expr AS alias`, the alias ends the expression
+func (p *Parser) parseAliasExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.AliasExpression{Token: p.curToken, Expr: left}
+	if !isAliasName(p.peekToken) {
+		return nil, errorAt(p.peekToken, "expected an alias after AS, got %q instead", p.peekToken.Type)
+	}
+	p.nextToken()
+
+	return p.finishAlias(expr)
+}
+
+// Parses the implicit alias of `expr alias`, the current token is the alias
+func (p *Parser) parseImplicitAlias(left ast.Expression) (ast.Expression, error) {
+	if p.opts.PeriodPredicates && isPeriodPredicate(p.curToken) {
+		return p.parsePeriodPredicate(left)
+	}
+
+	return p.finishAlias(&ast.AliasExpression{Token: p.curToken, Expr: left})
+}
+
+// Sets the current token as the alias, nothing but the end of the expression can follow it
+func (p *Parser) finishAlias(expr *ast.AliasExpression) (ast.Expression, error) {
+	expr.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if precedence, err := p.peekPrecedence(); err == nil && precedence > LOWEST {
+		return nil, errorAt(p.peekToken, "unexpected %q after the alias %s", p.peekToken.Literal, expr.Alias.Value)
+	}
+
+	return expr, nil
+}
+
+func isAliasName(tok token.Token) bool {
+	switch tok.Type {
+	case token.IDENT, token.BACK_QUOTE_IDENT, token.DOUBLE_QUOTE_IDENT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Parses `cond ? a : b`, which is right-associative,
+// so `a ? b : c ? d : e` is `a ? b : (c ? d : e)`
+func (p *Parser) parseTernaryExpression(cond ast.Expression) (ast.Expression, error) {
+	expr := &ast.TernaryExpression{Token: p.curToken, Cond: cond}
+
+	p.nextToken()
+	p.openTernaries++
+	var err error
+	expr.True, err = p.parseExpression(LOWEST)
+	p.openTernaries--
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPeek(token.COLON); err != nil {
+		return nil, err
+	}
+	p.nextToken()
+	expr.False, err = p.parseExpression(TERNARY - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// JSON access like `data -> 'a'` or `data ->> 0`, chains are left-associative
+// and `data -> 'a'::text` casts the accessed value rather than the key
+func (p *Parser) parseJSONAccessExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.JSONAccessExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	var err error
+	expr.Index, err = p.parseExpression(JSONACCESS)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// Subscripts like `a[1]`, `m['k']` or `f(x)[1]`
+func (p *Parser) parseIndexExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	release := p.stopAt(token.RBRACKET)
+	var err error
+	expr.Index, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPeek(token.RBRACKET); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) parseExpressionList(end token.Type) ([]ast.Expression, error) {
+	defer p.stopAt(end)()
+
+	var list []ast.Expression
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return list, nil
+	}
+
+	p.nextToken()
+	v, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseExpressionListFrom(v, end)
+}
+
+// Parses the rest of a comma-separated list after its first element
+func (p *Parser) parseExpressionListFrom(first ast.Expression, end token.Type) ([]ast.Expression, error) {
+	return p.parseListFrom(first, end, func() (ast.Expression, error) {
+		return p.parseExpression(LOWEST)
+	})
+}
+
+// Parses the rest of a comma-separated list after its first element,
+// each further element is parsed by parseElement with the current token at its start
+func (p *Parser) parseListFrom(first ast.Expression, end token.Type, parseElement func() (ast.Expression, error)) ([]ast.Expression, error) {
+	list, err := p.parseListElementsFrom(first, parseElement)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectPeek(end); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Parses the comma-separated elements after the first one, leaving the token after them
+func (p *Parser) parseListElementsFrom(first ast.Expression, parseElement func() (ast.Expression, error)) ([]ast.Expression, error) {
+	list := []ast.Expression{first}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		v, err := parseElement()
+		if err != nil {
+			return nil, err
+		}
 
 		list = append(list, v)
+		if p.opts.MaxListElements > 0 && len(list) > p.opts.MaxListElements {
+			return nil, errorAt(p.curToken, "too many list elements: the limit is %d", p.opts.MaxListElements)
+		}
+	}
+
+	return list, nil
+}
+
+func (p *Parser) parseBetweenExpression(left ast.Expression) (ast.Expression, error) {
+	r, err := p.parseBetweenRange()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &ast.BetweenExpression{
+		Left:  left,
+		Range: r,
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) parseNotBetweenExpression(left ast.Expression) (ast.Expression, error) {
+	r, err := p.parseBetweenRange()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &ast.NotBetweenExpression{
+		Left:  left,
+		Range: r,
+	}
+
+	return expr, nil
+}
+
+// Parses `lower AND upper` after `BETWEEN` or `NOT BETWEEN`.
+// Both bounds are parsed above `AND` precedence,
+// so `x BETWEEN a AND b AND c` is `((x BETWEEN a AND b) AND c)`
+func (p *Parser) parseBetweenRange() (*ast.InfixExpression, error) {
+	op := p.curToken.Type
+	switch p.peekToken.Type {
+	case token.AND:
+		return nil, errorAt(p.peekToken, "%s requires a lower bound before AND", op)
+	case token.EOF:
+		return nil, errorAt(p.peekToken, "%s requires a lower and upper bound", op)
+	}
+
+	p.nextToken()
+	lower, err := p.parseExpression(COND)
+	if err != nil {
+		if errors.Is(err, errNoPrecedence) {
+			return nil, errorAt(p.peekToken, "%s requires AND between the lower and upper bound, got %q", op, p.peekToken.Literal)
+		}
+		return nil, err
+	}
+
+	if !p.peekTokenIs(token.AND) {
+		return nil, errorAt(p.peekToken, "%s requires AND between the lower and upper bound, got %q", op, p.peekToken.Type)
+	}
+	p.nextToken()
+	r := &ast.InfixExpression{Token: p.curToken, Left: lower}
+
+	if p.peekTokenIs(token.EOF) {
+		return nil, errorAt(p.peekToken, "%s requires an upper bound after AND", op)
+	}
+
+	p.nextToken()
+	r.Right, err = p.parseExpression(COND)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (p *Parser) parseCollateExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.CollateExpression{Token: p.curToken, Left: left}
+
+	switch p.peekToken.Type {
+	case token.IDENT, token.BACK_QUOTE_IDENT, token.DOUBLE_QUOTE_IDENT:
+		p.nextToken()
+	default:
+		return nil, errorAt(p.peekToken, "expected collation name after COLLATE, got %q", p.peekToken.Type)
+	}
+	expr.Collation = &ast.Identifier{
+		Token:            p.curToken,
+		Value:            p.curToken.Literal,
+		BackslashEscapes: p.l.Options().BackslashEscapesInIdentifiers,
+	}
+
+	return expr, nil
+}
+
+// Parses `x [NOT] IN list`, a one-argument UNNEST call right after IN
+// is the BigQuery array form `x IN UNNEST(array)`, other UNNEST calls are generic
+func (p *Parser) parseInExpression(left ast.Expression) (ast.Expression, error) {
+	unnest := p.peekToken.Type == token.IDENT && strings.EqualFold(p.peekToken.Literal, "UNNEST")
+	expr, err := p.parseInfixExpression(left)
+	if err != nil {
+		return nil, err
+	}
+
+	infix := expr.(*ast.InfixExpression)
+	call, ok := infix.Right.(*ast.CallExpression)
+	if !unnest || !ok || len(call.Arguments) != 1 || call.Distinct || call.OrderBy != nil || call.WithinGroup != nil {
+		return infix, nil
+	}
+	if fn, ok := call.Fn.(*ast.Identifier); ok && strings.EqualFold(fn.Value, "UNNEST") {
+		infix.Right = &ast.UnnestExpression{Token: call.Token, Array: call.Arguments[0]}
+	}
+
+	return infix, nil
+}
+
+// POSITION(substr IN str)
+func (p *Parser) parsePositionExpression(fn ast.Expression) (ast.Expression, error) {
+	tok := p.curToken
+	if p.peekTokenIs(token.RPAREN) {
+		return p.parseGenericCallExpression(fn)
+	}
+
+	p.nextToken()
+	// Parse above `IN` precedence so `IN` is left as the separator
+	substr, err := p.parseExpression(IN)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.peekTokenIs(token.IN) {
+		return p.parseCallExpressionFrom(tok, fn, substr)
+	}
+	p.nextToken()
+	p.nextToken()
+
+	str, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return &ast.PositionExpression{Token: tok, Substr: substr, Str: str}, nil
+}
+
+// TRIM([LEADING | TRAILING | BOTH] [chars] FROM source)
+func (p *Parser) parseTrimExpression(fn ast.Expression) (ast.Expression, error) {
+	expr := &ast.TrimExpression{Token: p.curToken}
+	if p.peekTokenIs(token.RPAREN) {
+		return p.parseGenericCallExpression(fn)
+	}
+
+	p.nextToken()
+	// The spec is contextual, `TRIM(both)` or `TRIM(leading, chars)` trims a column
+	if spec, ok := token.LookupContextualKeyword(p.curToken.Literal); ok && p.curTokenIs(token.IDENT) && isTrimSpec(spec) &&
+		(p.peekTokenIs(token.FROM) || p.prefixParseFns[p.peekToken.Type] != nil) {
+		expr.Spec = string(spec)
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(token.FROM) {
+		release := p.stopAt(token.FROM)
+		chars, err := p.parseExpression(LOWEST)
+		release()
+		if err != nil {
+			return nil, err
+		}
+
+		// TRIM(source) or TRIM(source, chars)
+		if expr.Spec == "" && !p.peekTokenIs(token.FROM) {
+			return p.parseCallExpressionFrom(expr.Token, fn, chars)
+		}
+		expr.Chars = chars
+
+		if err := p.expectPeek(token.FROM); err != nil {
+			return nil, err
+		}
+	}
+	p.nextToken()
+
+	var err error
+	expr.Source, err = p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+func isTrimSpec(t token.Type) bool {
+	return t == token.LEADING || t == token.TRAILING || t == token.BOTH
+}
+
+// SUBSTRING(source FROM start [FOR length]), SUBSTRING(source FOR length)
+// or the regular expression form SUBSTRING(source SIMILAR pattern ESCAPE escape)
+func (p *Parser) parseSubstringExpression(fn ast.Expression) (ast.Expression, error) {
+	expr := &ast.SubstringExpression{Token: p.curToken}
+	if p.peekTokenIs(token.RPAREN) {
+		return p.parseGenericCallExpression(fn)
+	}
+
+	p.nextToken()
+	release := p.stopAt(token.FROM, token.FOR, token.SIMILAR)
+	var err error
+	expr.Source, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peekKeyword(token.SIMILAR) {
+		return p.parseSubstringSimilar(expr.Token, expr.Source)
+	}
+	if !p.peekTokenIs(token.FROM) && !p.peekKeyword(token.FOR) {
+		return p.parseCallExpressionFrom(expr.Token, fn, expr.Source)
+	}
+
+	if p.peekTokenIs(token.FROM) {
+		p.nextToken()
+		p.nextToken()
+		release := p.stopAt(token.FOR)
+		expr.From, err = p.parseExpression(LOWEST)
+		release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekKeyword(token.FOR) {
+		p.nextToken()
+		p.nextToken()
+		expr.For, err = p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// Parses the rest of SUBSTRING(source SIMILAR pattern ESCAPE escape) after the source,
+// ESCAPE is required by the standard form
+func (p *Parser) parseSubstringSimilar(tok token.Token, source ast.Expression) (ast.Expression, error) {
+	expr := &ast.SubstringSimilarExpression{Token: tok, Source: source}
+
+	p.nextToken()
+	p.nextToken()
+	release := p.stopAt(token.ESCAPE)
+	var err error
+	expr.Pattern, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
 	}
-	if err := p.expectPeek(end); err != nil {
+
+	if !p.peekKeyword(token.ESCAPE) {
+		return nil, p.expectPeek(token.ESCAPE)
+	}
+	expr.Escape, err = p.parseOptionalEscape()
+	if err != nil {
 		return nil, err
 	}
 
-	return list, nil
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
 }
 
-func (p *Parser) parseBetweenExpression(left ast.Expression) (ast.Expression, error) {
+// EXTRACT(field FROM source), the field is a time unit like YEAR or DAY.
+// Other arguments are a generic call, like the ClickHouse regular expression `extract(s, pattern)`
+func (p *Parser) parseExtractExpression(fn ast.Expression) (ast.Expression, error) {
+	if !p.peekToken.Type.IsTimeUnit() {
+		return p.parseGenericCallExpression(fn)
+	}
+
+	expr := &ast.ExtractExpression{Token: p.curToken}
+	p.nextToken()
+	expr.Field = p.curToken.Type
+
+	if !p.peekTokenIs(token.FROM) {
+		return nil, errorAt(p.peekToken, "expected FROM after EXTRACT(%s, got %q instead", expr.Field, p.peekToken.Literal)
+	}
+	p.nextToken()
 	p.nextToken()
-	r, err := p.parseExpression(LOWEST)
+	var err error
+	expr.Source, err = p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
-	v, ok := r.(*ast.InfixExpression)
-	if !ok {
-		return nil, fmt.Errorf("expected infix expression, got %s", r.TokenLiteral())
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// OVERLAY(source PLACING replacement FROM start [FOR length])
+func (p *Parser) parseOverlayExpression(fn ast.Expression) (ast.Expression, error) {
+	expr := &ast.OverlayExpression{Token: p.curToken}
+	if p.peekTokenIs(token.RPAREN) {
+		return p.parseGenericCallExpression(fn)
 	}
-	if v.Operator() != token.AND {
-		return nil, fmt.Errorf("expected AND, got %s", v.Operator())
+
+	p.nextToken()
+	release := p.stopAt(token.PLACING)
+	var err error
+	expr.Source, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
 	}
 
-	expr := &ast.BetweenExpression{
-		Left:  left,
-		Range: v,
+	// OVERLAY(source, replacement, ...)
+	if !p.peekKeyword(token.PLACING) {
+		return p.parseCallExpressionFrom(expr.Token, fn, expr.Source)
+	}
+	p.nextToken()
+	p.nextToken()
+	release = p.stopAt(token.FROM)
+	expr.Placing, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPeek(token.FROM); err != nil {
+		return nil, err
+	}
+	p.nextToken()
+	release = p.stopAt(token.FOR)
+	expr.From, err = p.parseExpression(LOWEST)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peekKeyword(token.FOR) {
+		p.nextToken()
+		p.nextToken()
+		expr.For, err = p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
 	}
 
 	return expr, nil
 }
 
-func (p *Parser) parseNotBetweenExpression(left ast.Expression) (ast.Expression, error) {
+// Parses `LIKE pattern` or the quantified form `LIKE ANY (pattern, ...)`
+func (p *Parser) parseLikeExpression(left ast.Expression) (ast.Expression, error) {
+	p.peekToken, _ = token.AllowDeniedKeyword(p.peekToken, token.ALL)
+	if !p.peekTokenIs(token.ANY) && !p.peekTokenIs(token.ALL) && !p.peekKeyword(token.SOME) {
+		return p.parseLikeEscape(left)
+	}
+
+	expr := &ast.LikeExpression{Token: p.curToken, Left: left}
+	p.nextToken()
+	expr.Quantifier = p.curToken.Type
+
+	if err := p.expectPeek(token.LPAREN); err != nil {
+		return nil, err
+	}
+
+	var err error
+	expr.Patterns, err = p.parseExpressionList(token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+	if len(expr.Patterns) == 0 {
+		return nil, errorAt(p.curToken, "%s %s requires at least one pattern", expr.Operator(), expr.Quantifier)
+	}
+
+	return expr, nil
+}
+
+// Parses `LIKE pattern [ESCAPE escape]`, the pattern and its escape are the right side
+func (p *Parser) parseLikeEscape(left ast.Expression) (ast.Expression, error) {
+	release := p.stopAt(token.ESCAPE)
+	expr, err := p.parseInfixExpression(left)
+	release()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekKeyword(token.ESCAPE) {
+		return expr, nil
+	}
+
+	infix := expr.(*ast.InfixExpression)
+	escape := &ast.EscapeExpression{Token: p.peekToken, Pattern: infix.Right}
+	escape.Escape, err = p.parseOptionalEscape()
+	if err != nil {
+		return nil, err
+	}
+	infix.Right = escape
+
+	return infix, nil
+}
+
+// Parses `ESCAPE escape` after a pattern, or returns nil when the next token is not ESCAPE.
+// A string literal escape must be exactly one character.
+func (p *Parser) parseOptionalEscape() (ast.Expression, error) {
+	if !p.peekKeyword(token.ESCAPE) {
+		return nil, nil
+	}
+	p.nextToken()
 	p.nextToken()
-	r, err := p.parseExpression(LOWEST)
+	tok := p.curToken
+	// Parse above `IN` like the pattern, so `a LIKE 'x' ESCAPE '!' AND b` ends the escape at AND
+	escape, err := p.parseExpression(IN)
 	if err != nil {
 		return nil, err
 	}
-	v, ok := r.(*ast.InfixExpression)
+
+	if s, ok := escape.(*ast.StringLiteral); ok && s.Token.Type == token.STRING && utf8.RuneCountInString(s.Decode()) != 1 {
+		return nil, errorAt(tok, "ESCAPE must be a single character, got %s", s.String())
+	}
+
+	return escape, nil
+}
+
+// Parses the right side of `IS` or `IS NOT`
+func (p *Parser) parseIsExpression(left ast.Expression) (ast.Expression, error) {
+	if p.peekKeyword(token.NORMALIZED) {
+		return p.parseIsNormalizedExpression(left)
+	}
+	if p.peekKeyword(token.OF) {
+		return p.parseIsOfExpression(left)
+	}
+	if p.opts.XMLPredicates && p.peekTokenIs(token.IDENT) && strings.ToUpper(p.peekToken.Literal) == token.DOCUMENT {
+		return p.parseIsDocumentExpression(left)
+	}
+	if p.opts.OracleSetPredicates && p.peekTokenIs(token.IDENT) && strings.ToUpper(p.peekToken.Literal) == token.A {
+		return p.parseIsASetExpression(left)
+	}
+	switch p.peekToken.Type {
+	case token.NULL, token.TRUE, token.FALSE:
+		expr := &ast.IsExpression{
+			Token:   p.curToken,
+			Left:    left,
+			Negated: p.curTokenIs(token.IS_NOT),
+		}
+		p.nextToken()
+		expr.Predicate = p.curToken.Type
+		expr.PredicateLiteral = p.curToken.Literal
+		return expr, nil
+	}
+
+	return p.parseInfixExpression(left)
+}
+
+// The words starting a period predicate, see Options.PeriodPredicates
+var periodPredicates = map[string]bool{
+	token.CONTAINS:    true,
+	token.PRECEDES:    true,
+	token.SUCCEEDS:    true,
+	token.IMMEDIATELY: true,
+}
+
+func isPeriodPredicate(tok token.Token) bool {
+	return tok.Type == token.IDENT && periodPredicates[strings.ToUpper(tok.Literal)]
+}
+
+// Parses `p1 CONTAINS p2`, `p1 [IMMEDIATELY] PRECEDES p2` or `p1 [IMMEDIATELY] SUCCEEDS p2`
+func (p *Parser) parsePeriodPredicate(left ast.Expression) (ast.Expression, error) {
+	tok := p.curToken
+	tok.Type = token.Type(strings.ToUpper(tok.Literal))
+	if tok.Type == token.IMMEDIATELY {
+		next := strings.ToUpper(p.peekToken.Literal)
+		if p.peekToken.Type != token.IDENT || (next != token.PRECEDES && next != token.SUCCEEDS) {
+			return nil, errorAt(p.peekToken, "expected PRECEDES or SUCCEEDS after IMMEDIATELY, got %q instead", p.peekToken.Literal)
+		}
+		p.nextToken()
+		tok.Type = token.Type(token.IMMEDIATELY + " " + next)
+		tok.Literal += " " + p.curToken.Literal
+		tok.End = p.curToken.End
+	}
+
+	expr := &ast.InfixExpression{Token: tok, Left: left}
+	p.nextToken()
+	var err error
+	expr.Right, err = p.parseExpression(EQUALS)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+var normalizationForms = map[string]bool{
+	"NFC":  true,
+	"NFD":  true,
+	"NFKC": true,
+	"NFKD": true,
+}
+
+// x IS [NOT] NORMALIZED [NFC | NFD | NFKC | NFKD]
+func (p *Parser) parseIsNormalizedExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.IsNormalizedExpression{
+		Token:   p.curToken,
+		Expr:    left,
+		Negated: p.curTokenIs(token.IS_NOT),
+	}
+	p.nextToken()
+
+	// The forms are not keywords, so they can still be used as identifiers elsewhere
+	if p.peekTokenIs(token.IDENT) {
+		if form := strings.ToUpper(p.peekToken.Literal); normalizationForms[form] {
+			p.nextToken()
+			expr.Form = form
+		}
+	}
+
+	return expr, nil
+}
+
+// x IS [NOT] DOCUMENT
+func (p *Parser) parseIsDocumentExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.IsDocumentExpression{
+		Token:   p.curToken,
+		Expr:    left,
+		Negated: p.curTokenIs(token.IS_NOT),
+	}
+	p.nextToken()
+
+	return expr, nil
+}
+
+// x IS [NOT] A SET
+func (p *Parser) parseIsASetExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.IsASetExpression{
+		Token:   p.curToken,
+		Expr:    left,
+		Negated: p.curTokenIs(token.IS_NOT),
+	}
+	p.nextToken()
+
+	set, ok := token.AllowDeniedKeyword(p.peekToken, token.SET)
 	if !ok {
-		return nil, fmt.Errorf("expected infix expression, got %s", r.TokenLiteral())
+		return nil, errorAt(p.peekToken, "expected SET after %s A, got %q instead", expr.Token.Type, p.peekToken.Literal)
+	}
+	p.peekToken = set
+	p.nextToken()
+
+	return expr, nil
+}
+
+// x IS [NOT] OF (type, ...)
+func (p *Parser) parseIsOfExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.IsOfExpression{
+		Token:   p.curToken,
+		Expr:    left,
+		Negated: p.curTokenIs(token.IS_NOT),
+	}
+	p.nextToken()
+	if err := p.expectPeek(token.LPAREN); err != nil {
+		return nil, err
 	}
-	if v.Operator() != token.AND {
-		return nil, fmt.Errorf("expected AND, got %s", v.Operator())
+	if p.peekTokenIs(token.RPAREN) {
+		return nil, errorAt(p.peekToken, "IS OF requires at least one type")
 	}
 
-	expr := &ast.NotBetweenExpression{
-		Left:  left,
-		Range: v,
+	for {
+		typ, err := p.parseTypeReference()
+		if err != nil {
+			return nil, err
+		}
+		expr.Types = append(expr.Types, typ)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// CAST(expr AS type [FORMAT 'format'])
+func (p *Parser) parseCastExpression(fn ast.Expression) (ast.Expression, error) {
+	expr := &ast.CastExpression{Token: p.curToken}
+
+	p.nextToken()
+	var err error
+	// Parse above `AS` precedence so `AS` is left as the separator
+	expr.Expr, err = p.parseExpression(AS)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.peekTokenIs(token.AS) {
+		return nil, errorAt(p.peekToken, "CAST requires AS before the target type, got %q", p.peekToken.Type)
+	}
+	p.nextToken()
+
+	expr.Type, err = p.parseTypeReference()
+	if err != nil {
+		return nil, wrapErrorAt(p.curToken, "CAST requires a target type after AS: %w", err)
+	}
+
+	// `FORMAT` is not a keyword, so `FORMAT(x, 2)` can still be called elsewhere
+	if p.peekTokenIs(token.IDENT) && strings.ToUpper(p.peekToken.Literal) == "FORMAT" {
+		p.nextToken()
+		if err := p.expectPeek(token.STRING); err != nil {
+			return nil, err
+		}
+		expr.Format = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// Postgres `x::type`, which binds tighter than a unary minus, so `-1::int` is `-(1::int)`
+func (p *Parser) parseDoubleColonCast(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.CastExpression{Token: p.curToken, Expr: left}
+
+	var err error
+	expr.Type, err = p.parseTypeName()
+	if err != nil {
+		return nil, wrapErrorAt(expr.Token, ":: requires a target type: %w", err)
+	}
+	// A `[` not followed by `]` is a subscript, `x::int[1]` is `(x::int)[1]`
+	if p.parseArrayTypeSuffix(expr.Type) {
+		return p.parseIndexExpression(expr)
+	}
+
+	return expr, nil
+}
+
+// Parses the next tokens as a type with optional arguments like `DECIMAL(10, 2)`
+// or an array type like `int[]`
+func (p *Parser) parseTypeReference() (*ast.TypeReference, error) {
+	typ, err := p.parseTypeName()
+	if err != nil {
+		return nil, err
+	}
+	if p.parseArrayTypeSuffix(typ) {
+		return nil, errorAt(p.peekToken, "expected %q after %q of the array type %s, got %q instead", token.RBRACKET, token.LBRACKET, typ.String(), p.peekToken.Type)
+	}
+
+	return typ, nil
+}
+
+// Parses the `[]` suffixes of an array type like `text[][]`.
+// It stops at a `[` not followed by `]`, which is left as the current token, and reports true.
+func (p *Parser) parseArrayTypeSuffix(typ *ast.TypeReference) bool {
+	for p.peekTokenIs(token.LBRACKET) {
+		p.nextToken()
+		if !p.peekTokenIs(token.RBRACKET) {
+			return true
+		}
+		p.nextToken()
+		typ.ArrayDims++
+	}
+
+	return false
+}
+
+// Parses the next tokens as a type name with optional arguments like `DECIMAL(10, 2)`
+func (p *Parser) parseTypeName() (*ast.TypeReference, error) {
+	switch p.peekToken.Type {
+	case token.IDENT, token.BACK_QUOTE_IDENT, token.DOUBLE_QUOTE_IDENT:
+		p.nextToken()
+	default:
+		return nil, errorAt(p.peekToken, "expected type name, got %q", p.peekToken.Type)
+	}
+
+	typ := &ast.TypeReference{Token: p.curToken, Name: p.curToken.Literal}
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		args, err := p.parseExpressionList(token.RPAREN)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return nil, errorAt(p.curToken, "empty `()` after type %s is not supported", typ.Name)
+		}
+		typ.Args = args
+	}
+
+	return typ, nil
+}
+
+// Tokens allowed as the symbol of `OPERATOR(schema.op)`
+var operatorSymbols = map[token.Type]bool{
+	token.PLUS:          true,
+	token.MINUS:         true,
+	token.ASTERISK:      true,
+	token.SLASH:         true,
+	token.MOD:           true,
+	token.XOR:           true,
+	token.PIPE:          true,
+	token.PIPE2:         true,
+	token.AMP:           true,
+	token.TILDE:         true,
+	token.BANG:          true,
+	token.LT2:           true,
+	token.RT2:           true,
+	token.EQ:            true,
+	token.BANG_EQ:       true,
+	token.NOT_EQ:        true,
+	token.LT:            true,
+	token.LT_EQ:         true,
+	token.GT:            true,
+	token.GT_EQ:         true,
+	token.PRT:           true,
+	token.PRT2:          true,
+	token.HASH_GT:       true,
+	token.HASH_GT2:      true,
+	token.AT_GT:         true,
+	token.LT_AT:         true,
+	token.AMP2:          true,
+	token.QUESTION:      true,
+	token.QUESTION_PIPE: true,
+	token.QUESTION_AMP:  true,
+}
+
+// a OPERATOR(schema.op) b
+func (p *Parser) parseCustomOperatorExpression(left ast.Expression) (ast.Expression, error) {
+	expr := &ast.CustomOperatorExpression{Token: p.curToken, Left: left}
+	if err := p.expectPeek(token.LPAREN); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		b.WriteString(p.curToken.Literal)
+		if err := p.expectPeek(token.PERIOD); err != nil {
+			return nil, err
+		}
+		b.WriteString(p.curToken.Literal)
+	}
+
+	p.nextToken()
+	if !operatorSymbols[p.curToken.Type] {
+		return nil, errorAt(p.curToken, "expected operator symbol in OPERATOR(), got %q", p.curToken.Literal)
+	}
+	b.WriteString(p.curToken.Literal)
+	expr.Operator = b.String()
+
+	if err := p.expectPeek(token.RPAREN); err != nil {
+		return nil, err
+	}
+
+	p.nextToken()
+	right, err := p.parseExpression(OTHER)
+	if err != nil {
+		return nil, err
 	}
+	expr.Right = right
 
 	return expr, nil
 }
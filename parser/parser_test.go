@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/chenjunwen186/sqlexpr/ast"
@@ -84,6 +87,14 @@ func TestBooleanLiteral(t *testing.T) {
 	}
 }
 
+func TestTypedStringLiteral(t *testing.T) {
+	expr := parseExpression(t, "a = x'4F' OR b = B'0101'")
+	expected := "((a = x'4F') OR (b = B'0101'))"
+	if expr.String() != expected {
+		t.Errorf("expr.String() not %q, got %q", expected, expr.String())
+	}
+}
+
 func TestEmptyInput(t *testing.T) {
 	input := ``
 	expr := parseExpression(t, input)
@@ -104,6 +115,7 @@ func TestPrefixExpression(t *testing.T) {
 		{"-123", "-", 123, "(-123)"},
 		{"+123.456", "+", 123.456, "(+123.456)"},
 		{"DISTINCT hello", "DISTINCT", "hello", "(DISTINCT hello)"},
+		{"~x", "~", "x", "(~x)"},
 	}
 	for _, input := range inputs {
 		expr := parseExpression(t, input.input)
@@ -123,8 +135,8 @@ func TestGroupedExpression(t *testing.T) {
 	_, err := parseExpressionWithError(t, inputEmpty)
 	if err == nil {
 		t.Errorf("should parsed error, but not")
-	} else if err.Error() != "empty `()` is not supported" {
-		t.Errorf("err.Error() should be: empty `()` is not supported")
+	} else if err.Error() != "1:1: empty `()` is not supported" {
+		t.Errorf("err.Error() should be: 1:1: empty `()` is not supported, got %s", err)
 	}
 }
 
@@ -186,246 +198,916 @@ func TestInfixExpression(t *testing.T) {
 	}
 }
 
-func TestBetweenExpression(t *testing.T) {
+func TestJSONOperators(t *testing.T) {
 	type TestCase struct {
 		input string
-		left  any
-		expr  string
+		str   string
 	}
 
 	inputs := []TestCase{
-		{"123 between 456 and 789", 123, "(456 AND 789)"},
+		{"data #> '{a,b}'", "(data #> '{a,b}')"},
+		{"data #>> '{a,b}'", "(data #>> '{a,b}')"},
+		{"data -> 'a'", "(data -> 'a')"},
+		{"data ->> 'a'", "(data ->> 'a')"},
+		{"data -> 'a' -> 'b'", "((data -> 'a') -> 'b')"},
+		{"data #>> '{a}' = 'x'", "((data #>> '{a}') = 'x')"},
+		{"data #> i + 1", "(data #> (i + 1))"},
+		{"a + b #> c", "((a + b) #> c)"},
+		{"a #> b @> c", "((a #> b) @> c)"},
+		{"data #> 'a' | b", "(data #> ('a' | b))"},
 	}
 	for _, input := range inputs {
-		expr := parseExpression(t, input.input)
-		v, ok := expr.(*ast.BetweenExpression)
-		if !ok {
-			t.Errorf("expr not *ast.BetweenExpression, got %T", expr)
+		p := New(lexer.NewWithOptions(input.input, lexer.Options{JSONPathOperators: true}))
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
 			continue
 		}
-		testLiteralExpression(t, v.Left, input.left)
-		if v.Range.String() != input.expr {
-			t.Errorf("v.Range.String() not %q, got %q", input.expr, v.Range.String())
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	errInputs := []string{"data # > '{a}'", "data #> ", "#> data"}
+	for _, input := range errInputs {
+		p := New(lexer.NewWithOptions(input, lexer.Options{JSONPathOperators: true}))
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
+	}
+
+	// Without the lexer option `#>` starts a comment, which MySQL would ignore
+	for _, input := range []string{"a = 1 #> b", "data #>> '{a}'"} {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
 		}
 	}
 }
 
-func TestNotBetweenExpression(t *testing.T) {
+// Containment operators bind tighter than comparisons and group left among themselves
+func TestContainmentOperators(t *testing.T) {
 	type TestCase struct {
 		input string
-		left  any
-		expr  string
+		str   string
 	}
 
 	inputs := []TestCase{
-		{"123 not between 456 and 789", 123, "(456 AND 789)"},
+		{"tags @> '{a}'", "(tags @> '{a}')"},
+		{"'{a}' <@ tags", "('{a}' <@ tags)"},
+		{"r && s", "(r && s)"},
+		{"a @> b = c", "((a @> b) = c)"},
+		{"c = a @> b", "(c = (a @> b))"},
+		{"a <@ b <> c", "((a <@ b) <> c)"},
+		{"a && b @> c", "((a && b) @> c)"},
+		{"a @> b <@ c", "((a @> b) <@ c)"},
+		{"a <@ b && c", "((a <@ b) && c)"},
+		{"a @> b < c", "((a @> b) < c)"},
+		{"a @> b + c", "(a @> (b + c))"},
+		{"a | b && c", "((a | b) && c)"},
+		{"a @> b AND c <@ d", "((a @> b) AND (c <@ d))"},
+		{"NOT a && b", "(NOT (a && b))"},
+		{"a @> b IN (c)", "((a @> b) IN c)"},
+		{"a -> 'k' @> b", "((a -> 'k') @> b)"},
 	}
 	for _, input := range inputs {
 		expr := parseExpression(t, input.input)
-		v, ok := expr.(*ast.NotBetweenExpression)
-		if !ok {
-			t.Errorf("expr not *ast.NotBetweenExpression, got %T", expr)
-			continue
+		if expr.String() != input.str {
+			t.Errorf("%q: expr.String() not %q, got %q", input.input, input.str, expr.String())
 		}
-		testLiteralExpression(t, v.Left, input.left)
-		if v.Range.String() != input.expr {
-			t.Errorf("v.Range.String() not %q, got %q", input.expr, v.Range.String())
+	}
+
+	errInputs := []string{"a @ > b", "a < @ b", "a & & b", "@> a", "a <@"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
 		}
 	}
 }
 
-func testIdentifier(t *testing.T, exp ast.Expression, value string) bool {
-	ident, ok := exp.(*ast.Identifier)
-	if !ok {
-		t.Errorf("exp not *ast.Identifier, got %T", exp)
-		return false
+func TestJSONAccessExpression(t *testing.T) {
+	type TestCase struct {
+		input    string
+		operator token.Type
+		str      string
 	}
 
-	if ident.Value != value {
-		t.Errorf("ident.Value not %q, got %q", value, ident.Value)
-		return false
+	inputs := []TestCase{
+		{"col -> 'key'", token.PRT, "(col -> 'key')"},
+		{"col ->> 0", token.PRT2, "(col ->> 0)"},
+		{"data->'a'->>'b'", token.PRT2, "((data -> 'a') ->> 'b')"},
+		{"data -> 'a' -> 0 ->> 'b'", token.PRT2, "(((data -> 'a') -> 0) ->> 'b')"},
+		{"f(x) -> 'a'", token.PRT, "(f(x) -> 'a')"},
+		{"data -> lower(x)", token.PRT, "(data -> lower(x))"},
+		{"data -> -1", token.PRT, "(data -> (-1))"},
+		{"data -> (i + 1)", token.PRT, "(data -> (i + 1))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		access, ok := expr.(*ast.JSONAccessExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.JSONAccessExpression, got %T", input.input, expr)
+			continue
+		}
+		if access.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, access.Operator())
+		}
 	}
 
-	return true
+	// JSON access binds tighter than every operator except subscripts and calls
+	bindings := []TestCase{
+		{"data ->> 'a' = 'x'", token.EQ, "((data ->> 'a') = 'x')"},
+		{"data -> i + 1", token.PLUS, "((data -> i) + 1)"},
+		{"-data -> 'a'", token.MINUS, "(-(data -> 'a'))"},
+		{"data ->> 'a'::int", token.COLON2, "((data ->> 'a')::int)"},
+		{"data ->> 'a' IS NULL", token.IS, "((data ->> 'a') IS NULL)"},
+		{"a + b -> 'c'", token.PLUS, "(a + (b -> 'c'))"},
+	}
+	for _, input := range bindings {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	errInputs := []string{"data ->", "data ->> ", "-> 'a'", "data -> ->> 'a'"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
+	}
 }
 
-func testBooleanLiteral(t *testing.T, exp ast.Expression, value bool) bool {
-	v, ok := exp.(*ast.BooleanLiteral)
-	if !ok {
-		t.Errorf("exp not *ast.BooleanLiteral, got %T", exp)
-		return false
+func TestBitwiseOperators(t *testing.T) {
+	type TestCase struct {
+		input    string
+		operator token.Type
+		str      string
 	}
-	if v.Value() != value {
-		t.Errorf("v.Value() not %t, got %t", value, v.Value())
-		return false
+
+	inputs := []TestCase{
+		{"a | b", token.PIPE, "(a | b)"},
+		{"a & b", token.AMP, "(a & b)"},
+		{"a ^ b", token.XOR, "(a ^ b)"},
+		{"x << 2", token.LT2, "(x << 2)"},
+		{"x >> 2", token.RT2, "(x >> 2)"},
+		{"a | b & c", token.PIPE, "(a | (b & c))"},
+		{"a & b | c", token.PIPE, "((a & b) | c)"},
+		{"a | b ^ c & d", token.PIPE, "(a | (b ^ (c & d)))"},
+		{"a & b << 1", token.AMP, "(a & (b << 1))"},
+		// Shifts bind looser than `+` and `-`
+		{"1 << 2 + 3", token.LT2, "(1 << (2 + 3))"},
+		{"1 + 2 >> 3", token.RT2, "((1 + 2) >> 3)"},
+		{"x << 1 << 2", token.LT2, "((x << 1) << 2)"},
+		// Bitwise operators bind tighter than comparisons
+		{"flags & 4 = 4", token.EQ, "((flags & 4) = 4)"},
+		{"a | b > c", token.GT, "((a | b) > c)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		infix, ok := expr.(*ast.InfixExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.InfixExpression, got %T", input.input, expr)
+			continue
+		}
+		if infix.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, infix.Operator())
+		}
+	}
+
+	errInputs := []string{"a |", "& a", "a << ", "a < < b"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
 	}
-	return true
 }
 
-func testNumberLiteral(t *testing.T, exp ast.Expression, expected any) bool {
-	v, ok := exp.(*ast.NumberLiteral)
-	if !ok {
-		t.Errorf("exp not *ast.NumberLiteral, got %T", exp)
-		return false
+func TestJSONBOperators(t *testing.T) {
+	type TestCase struct {
+		input    string
+		operator token.Type
+		str      string
 	}
-	switch exp := expected.(type) {
-	case int:
-		i, err := strconv.ParseInt(v.Literal, 10, 64)
+
+	inputs := []TestCase{
+		{"data ? 'key'", token.QUESTION, "(data ? 'key')"},
+		{"data ?| keys", token.QUESTION_PIPE, "(data ?| keys)"},
+		{"data ?& keys", token.QUESTION_AMP, "(data ?& keys)"},
+		{"data -> 'a' ? 'b'", token.QUESTION, "((data -> 'a') ? 'b')"},
+		{"data ? 'a' AND data ? 'b'", token.AND, "((data ? 'a') AND (data ? 'b'))"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{JSONBOperators: true})
+		expr, err := p.ParseExpression()
 		if err != nil {
-			t.Errorf("strconv.ParseInt(%q) failed: %s", v.Literal, err)
-			return false
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
 		}
-		if i != int64(exp) {
-			t.Errorf("i not %d, got %d", exp, i)
-			return false
+		infix, ok := expr.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("%q: expr is not *ast.InfixExpression, got %T", input.input, expr)
 		}
-
-		return true
-	case float64:
-		f, err := strconv.ParseFloat(v.Literal, 64)
-		if err != nil {
-			t.Errorf("strconv.ParseFloat(%q) failed: %s", v.Literal, err)
-			return false
+		if infix.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, infix.Operator())
 		}
-		if f != exp {
-			t.Errorf("f not %f, got %f", exp, f)
-			return false
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
 		}
-
-		return true
 	}
 
-	t.Errorf("type of expected not handled, got %T", expected)
-	return false
-}
-
-func testNullLiteral(t *testing.T, exp ast.Expression) bool {
-	_, ok := exp.(*ast.NullLiteral)
-	if !ok {
-		t.Errorf("exp not *ast.NullLiteral, got %T", exp)
-		return false
+	// Disabled by default
+	for _, input := range []string{"data ? 'key'", "data ?| keys", "data ?& keys"} {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error by default", input)
+		}
 	}
-	return true
 }
 
-func testLiteralExpression(t *testing.T, expr ast.Expression, expected any) bool {
-	switch v := expected.(type) {
-	case int:
-		return testNumberLiteral(t, expr, v)
-	case float64:
-		return testNumberLiteral(t, expr, v)
-	case string:
-		return testIdentifier(t, expr, v)
-	case bool:
-		return testBooleanLiteral(t, expr, v)
-	case nil:
-		return testNullLiteral(t, expr)
+func TestTernaryExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
 	}
-	t.Errorf("type of exp not handled, got %T", expr)
-	return false
-}
 
-func testPrefixExpression(t *testing.T, expr ast.Expression, operator string, right any) bool {
-	prefix, ok := expr.(*ast.PrefixExpression)
-	if !ok {
-		t.Errorf("expr not *ast.PrefixExpression, got %T", expr)
-		return false
+	inputs := []TestCase{
+		{"1 ? 2 : 3", "(1 ? 2 : 3)"},
+		{"a ? b : c ? d : e", "(a ? b : (c ? d : e))"},
+		{"a ? b ? c : d : e", "(a ? (b ? c : d) : e)"},
+		{"(a ? b : c) ? d : e", "((a ? b : c) ? d : e)"},
+		{"x > 1 AND y ? a + 1 : -b", "(((x > 1) AND y) ? (a + 1) : (-b))"},
 	}
-	if prefix.Operator() != operator {
-		t.Errorf("prefix.Operator not %q, got %q", operator, prefix.Operator())
-		return false
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if _, ok := expr.(*ast.TernaryExpression); !ok {
+			t.Errorf("%q: expr not *ast.TernaryExpression, got %T", input.input, expr)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
 	}
-	if !testLiteralExpression(t, prefix.Right, right) {
-		return false
+
+	nested := []TestCase{
+		{"f(a ? 1 : 2, b)", "f((a ? 1 : 2), b)"},
+		{"CASE WHEN a ? b : c THEN 1 END", "CASE WHEN (a ? b : c) THEN 1 END"},
+	}
+	for _, input := range nested {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
 	}
-	return true
-}
 
-func testInfixExpression(t *testing.T, expr ast.Expression, left any, operator token.Type, right any) bool {
-	switch infix := expr.(type) {
-	case *ast.InfixExpression:
-		{
-			if infix.Operator() != operator {
-				t.Errorf("infix.Operator not %q, got %q", operator, infix.Operator())
-				return false
-			}
-			if !testLiteralExpression(t, infix.Left, left) {
-				return false
-			}
-			if !testLiteralExpression(t, infix.Right, right) {
-				return false
-			}
-			return true
+	expr := parseExpression(t, "a ? 1 : 2").(*ast.TernaryExpression)
+	testIdentifier(t, expr.Cond, "a")
+	testNumberLiteral(t, expr.True, 1)
+	testNumberLiteral(t, expr.False, 2)
+
+	errInputs := []string{"a ? b", "a ? b c", "a ? : c", "a ? b :", "a : b", "a ? b : c : d"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
 		}
 	}
 
-	t.Errorf("expr not *ast.InfixExpression, got %T", expr)
-	return false
+	// `?` is the key-exists operator with JSONBOperators
+	p := NewWithOptions(lexer.New("a ? b : c"), Options{JSONBOperators: true})
+	if _, err := p.ParseExpression(); err == nil {
+		t.Errorf("a ? b : c should parsed error with JSONBOperators, but not")
+	}
 }
 
-func TestCallExpression(t *testing.T) {
+func TestNamedArguments(t *testing.T) {
 	type TestCase struct {
-		input  string
-		fnName string
-		args   []string
+		input string
+		str   string
 	}
 
 	inputs := []TestCase{
-		{"hello()", "hello", []string{}},
-		{"hello(123)", "hello", []string{"123"}},
-		{`hello(123, 0.456)`, "hello", []string{"123", "0.456"}},
-		// {`hello(123, x + y, x * y)`, "hello", []string{"123", "(x + y)", "(x * y)"}},
+		{"f(x => 1, y => 2)", "f(x => 1, y => 2)"},
+		{"f(a, b + 1, sep => ',')", "f(a, (b + 1), sep => ',')"},
+		{"f(x => a = 1 AND b)", "f(x => ((a = 1) AND b))"},
+		{"f(x => g(y => 2))", "f(x => g(y => 2))"},
+		{"f(x=>1) + 1", "(f(x => 1) + 1)"},
 	}
 	for _, input := range inputs {
 		expr := parseExpression(t, input.input)
-		testCallExpression(t, expr, input.fnName, input.args)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
 	}
-}
 
-func testCallExpression(t *testing.T, expr ast.Expression, fnName string, args []string) bool {
-	call, ok := expr.(*ast.CallExpression)
+	expr := parseExpression(t, "f(a, x => 1)")
+	arg, ok := expr.(*ast.CallExpression).Arguments[1].(*ast.NamedArgument)
 	if !ok {
-		t.Errorf("expr not *ast.CallExpression, got %T", expr)
-		return false
+		t.Fatalf("second argument is not *ast.NamedArgument, got %T", expr.(*ast.CallExpression).Arguments[1])
 	}
-	if call.Fn.String() != fnName {
-		t.Errorf("call.Function.Value not %q, got %q", fnName, call.Fn.String())
-		return false
+	testIdentifier(t, arg.Name, "x")
+	testNumberLiteral(t, arg.Value, 1)
+
+	type ErrTestCase struct {
+		input string
+		err   string
 	}
 
-	if len(call.Arguments) != len(args) {
-		t.Errorf("len(call.Arguments) not %d, got %d", len(args), len(call.Arguments))
-		return false
+	errInputs := []ErrTestCase{
+		{"f(x => 1, a)", "1:11: positional argument a cannot follow named arguments"},
+		{"f(x => 1, X => 2)", "1:11: duplicate named argument X"},
+		{"f(1 => 2)", "1:5: expected argument name before =>, got 1"},
+		{"f(a.b => 2)", ""},
+		{"x => 1", "1:3: unexpected => outside of function call arguments"},
+		{"(x => 1)", ""},
+		{"f(x => )", ""},
+		{"f(x => 1 => 2)", ""},
 	}
-	for i, arg := range args {
-		expected := call.Arguments[i].String()
-		if arg != expected {
-			t.Errorf("call.Arguments[%d].String() not %q, got %q", i, arg, expected)
-			return false
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should be parsed with error", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
 		}
 	}
-	return true
 }
 
-func TestCaseWhenExpression(t *testing.T) {
-	type WhenCase struct {
-		condition string
-		result    string
-	}
-
+func TestOperatorAliases(t *testing.T) {
 	type TestCase struct {
-		input      string
-		whens      []WhenCase
-		elseResult string
+		input    string
+		operator token.Type
+		literal  string
+		str      string
 	}
 
 	inputs := []TestCase{
-		{
-			"CASE WHEN x > 0 THEN 1 WHEN x < 0 THEN -1 ELSE 0 END",
-			[]WhenCase{
-				{"(x > 0)", "1"},
-				{"(x < 0)", "(-1)"},
-			},
+		// `!=` is already read by the constructor
+		{"a != b", token.NOT_EQ, "!=", "(a <> b)"},
+		{"a <> b", token.NOT_EQ, "<>", "(a <> b)"},
+		{"a & b", token.AND, "&", "(a AND b)"},
+		{"a != b & c = d", token.AND, "&", "((a <> b) AND (c = d))"},
+	}
+	for _, input := range inputs {
+		p := New(lexer.New(input.input))
+		p.RegisterOperatorAlias(token.BANG_EQ, token.NOT_EQ)
+		p.RegisterOperatorAlias(token.AMP, token.AND)
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
+		}
+
+		infix := expr.(*ast.InfixExpression)
+		if infix.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, infix.Operator())
+		}
+		if infix.Token.Literal != input.literal {
+			t.Errorf("%q: literal not %q, got %q", input.input, input.literal, infix.Token.Literal)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	p := New(lexer.New("a != b"))
+	p.RegisterOperatorAlias(token.BANG_EQ, token.NOT_EQ)
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	if actual := ast.Render(expr, ast.RenderOptions{OriginalOperators: true}); actual != "(a != b)" {
+		t.Errorf("Render() with original operators not %q, got %q", "(a != b)", actual)
+	}
+
+	// A symbolic alias of a keyword operator keeps its spelling regardless of the keyword case
+	p = New(lexer.New("a & not b"))
+	p.RegisterOperatorAlias(token.AMP, token.AND)
+	expr, err = p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	opts := ast.RenderOptions{KeywordCase: ast.KeywordUpper, OriginalOperators: true}
+	if actual := ast.Render(expr, opts); actual != "(a & (NOT b))" {
+		t.Errorf("Render() with original operators not %q, got %q", "(a & (NOT b))", actual)
+	}
+
+	// Not aliased by default
+	expr = parseExpression(t, "a != b")
+	testInfixExpression(t, expr, "a", token.BANG_EQ, "b")
+}
+
+func TestInUnnest(t *testing.T) {
+	type TestCase struct {
+		input    string
+		operator token.Type
+		array    string
+		str      string
+	}
+
+	inputs := []TestCase{
+		{"x IN UNNEST(tags)", token.IN, "tags", "(x IN UNNEST(tags))"},
+		{"x not in unnest(tags)", token.NOT_IN, "tags", "(x NOT IN UNNEST(tags))"},
+		{"x + 1 IN UNNEST(f(a))", token.IN, "f(a)", "((x + 1) IN UNNEST(f(a)))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+
+		infix, ok := expr.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("%q: expr is not *ast.InfixExpression, got %T", input.input, expr)
+		}
+		if infix.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, infix.Operator())
+		}
+		if !infix.IsInUnnest() {
+			t.Errorf("%q: IsInUnnest() should be true", input.input)
+		}
+		if unnest := infix.Right.(*ast.UnnestExpression); unnest.Array.String() != input.array {
+			t.Errorf("%q: array not %q, got %q", input.input, input.array, unnest.Array.String())
+		}
+	}
+
+	// Other forms of UNNEST are generic calls, like a plain call outside of IN
+	for _, input := range []string{"UNNEST(a, b)", "UNNEST()", "UNNEST(tags)", "f(UNNEST(tags))"} {
+		expr := parseExpression(t, input)
+		if _, ok := expr.(*ast.CallExpression); !ok {
+			t.Errorf("%q is not *ast.CallExpression, got %T", input, expr)
+		}
+	}
+
+	if parseExpression(t, "x IN (1, 2)").(*ast.InfixExpression).IsInUnnest() {
+		t.Errorf("x IN (1, 2) is not IN UNNEST")
+	}
+	for _, input := range []string{"x = UNNEST(tags)", "x IN (UNNEST(tags))", "x IN UNNEST(a, b)"} {
+		if infix := parseExpression(t, input).(*ast.InfixExpression); infix.IsInUnnest() {
+			t.Errorf("%q is not IN UNNEST", input)
+		}
+	}
+}
+
+func TestCanonicalKeywordOperators(t *testing.T) {
+	type TestCase struct {
+		input  string
+		symbol string
+		str    string
+	}
+
+	inputs := []TestCase{
+		{"x aNd y", "AND", "(x AND y)"},
+		{"x or y", "OR", "(x OR y)"},
+		{"x in y", "IN", "(x IN y)"},
+		{"x not In y", "NOT IN", "(x NOT IN y)"},
+		{"x like y", "LIKE", "(x LIKE y)"},
+		{"x NOT like y", "NOT LIKE", "(x NOT LIKE y)"},
+		{"x is y", "IS", "(x IS y)"},
+		{"x iS nOT y", "IS NOT", "(x IS NOT y)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.InfixExpression)
+		if !ok {
+			t.Errorf("expr not *ast.InfixExpression, got %T", expr)
+			continue
+		}
+		if v.Symbol() != input.symbol {
+			t.Errorf("v.Symbol() not %q, got %q", input.symbol, v.Symbol())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	others := []TestCase{
+		{"distinct x", "DISTINCT", "(DISTINCT x)"},
+		{"x between a and b", "", "(x BETWEEN (a AND b))"},
+		{"x not Between a and b", "", "(x NOT BETWEEN (a AND b))"},
+		{"x collate c", "", "(x COLLATE c)"},
+	}
+	for _, input := range others {
+		expr := parseExpression(t, input.input)
+		if v, ok := expr.(*ast.PrefixExpression); ok && v.Symbol() != input.symbol {
+			t.Errorf("v.Symbol() not %q, got %q", input.symbol, v.Symbol())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+}
+
+func TestCollateExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"a COLLATE c", "(a COLLATE c)"},
+		{"a collate `utf8mb4_bin`", "(a COLLATE `utf8mb4_bin`)"},
+		{"a COLLATE c = b COLLATE d", "((a COLLATE c) = (b COLLATE d))"},
+		{"a = b COLLATE c", "(a = (b COLLATE c))"},
+		{"a COLLATE c LIKE 'x%'", "((a COLLATE c) LIKE 'x%')"},
+		{"a COLLATE c < b AND x COLLATE y <> z", "(((a COLLATE c) < b) AND ((x COLLATE y) <> z))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	_, err := parseExpressionWithError(t, "a COLLATE 1")
+	if err == nil {
+		t.Errorf("should parsed error, but not")
+	}
+
+	collationNames := []struct {
+		input            string
+		backslashEscapes bool
+		expected         string
+	}{
+		{"a COLLATE `x``y`", false, "x`y"},
+		{"a COLLATE `x\\`", false, "x\\"},
+		{"a COLLATE `x\\`y`", true, "x`y"},
+		{`a COLLATE "x\"y"`, true, `x"y`},
+	}
+	for _, input := range collationNames {
+		l := lexer.NewWithOptions(input.input, lexer.Options{BackslashEscapesInIdentifiers: input.backslashEscapes})
+		expr, err := New(l).ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
+		}
+
+		collation := expr.(*ast.CollateExpression).Collation
+		if collation.Name() != input.expected {
+			t.Errorf("collation name of %q not %q, got %q", input.input, input.expected, collation.Name())
+		}
+	}
+}
+
+func TestIgnoreWhitespaceTokens(t *testing.T) {
+	l := lexer.NewWithOptions(" x \t+\n y  nOt  iN  z ", lexer.Options{EmitWhitespace: true})
+	p := New(l)
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+
+	expected := "((x + y) NOT IN z)"
+	if expr.String() != expected {
+		t.Errorf("expr.String() not %q, got %q", expected, expr.String())
+	}
+}
+
+func TestBetweenExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		left  any
+		expr  string
+	}
+
+	inputs := []TestCase{
+		{"123 between 456 and 789", 123, "(456 AND 789)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.BetweenExpression)
+		if !ok {
+			t.Errorf("expr not *ast.BetweenExpression, got %T", expr)
+			continue
+		}
+		testLiteralExpression(t, v.Left, input.left)
+		if v.Range.String() != input.expr {
+			t.Errorf("v.Range.String() not %q, got %q", input.expr, v.Range.String())
+		}
+	}
+}
+
+func TestNotBetweenExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		left  any
+		expr  string
+	}
+
+	inputs := []TestCase{
+		{"123 not between 456 and 789", 123, "(456 AND 789)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.NotBetweenExpression)
+		if !ok {
+			t.Errorf("expr not *ast.NotBetweenExpression, got %T", expr)
+			continue
+		}
+		testLiteralExpression(t, v.Left, input.left)
+		if v.Range.String() != input.expr {
+			t.Errorf("v.Range.String() not %q, got %q", input.expr, v.Range.String())
+		}
+	}
+}
+
+func TestBetweenExpressionGrouping(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"x BETWEEN a AND b AND c", "((x BETWEEN (a AND b)) AND c)"},
+		{"x NOT BETWEEN a AND b OR c", "((x NOT BETWEEN (a AND b)) OR c)"},
+		{"x BETWEEN a + 1 AND b * 2", "(x BETWEEN ((a + 1) AND (b * 2)))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+}
+
+func TestBetweenExpressionError(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"x BETWEEN AND b", "1:11: BETWEEN requires a lower bound before AND"},
+		{"x BETWEEN a b", `1:13: BETWEEN requires AND between the lower and upper bound, got "b"`},
+		{"x BETWEEN a OR b", `1:13: BETWEEN requires AND between the lower and upper bound, got "OR"`},
+		{"x BETWEEN a AND", "1:16: BETWEEN requires an upper bound after AND"},
+		{"x BETWEEN", "1:10: BETWEEN requires a lower and upper bound"},
+		{"x NOT BETWEEN a", `1:16: NOT BETWEEN requires AND between the lower and upper bound, got "EOF"`},
+	}
+	for _, input := range inputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func testIdentifier(t *testing.T, exp ast.Expression, value string) bool {
+	ident, ok := exp.(*ast.Identifier)
+	if !ok {
+		t.Errorf("exp not *ast.Identifier, got %T", exp)
+		return false
+	}
+
+	if ident.Value != value {
+		t.Errorf("ident.Value not %q, got %q", value, ident.Value)
+		return false
+	}
+
+	return true
+}
+
+func testBooleanLiteral(t *testing.T, exp ast.Expression, value bool) bool {
+	v, ok := exp.(*ast.BooleanLiteral)
+	if !ok {
+		t.Errorf("exp not *ast.BooleanLiteral, got %T", exp)
+		return false
+	}
+	if v.Value() != value {
+		t.Errorf("v.Value() not %t, got %t", value, v.Value())
+		return false
+	}
+	return true
+}
+
+func testNumberLiteral(t *testing.T, exp ast.Expression, expected any) bool {
+	v, ok := exp.(*ast.NumberLiteral)
+	if !ok {
+		t.Errorf("exp not *ast.NumberLiteral, got %T", exp)
+		return false
+	}
+	switch exp := expected.(type) {
+	case int:
+		i, err := strconv.ParseInt(v.Literal, 10, 64)
+		if err != nil {
+			t.Errorf("strconv.ParseInt(%q) failed: %s", v.Literal, err)
+			return false
+		}
+		if i != int64(exp) {
+			t.Errorf("i not %d, got %d", exp, i)
+			return false
+		}
+
+		return true
+	case float64:
+		f, err := strconv.ParseFloat(v.Literal, 64)
+		if err != nil {
+			t.Errorf("strconv.ParseFloat(%q) failed: %s", v.Literal, err)
+			return false
+		}
+		if f != exp {
+			t.Errorf("f not %f, got %f", exp, f)
+			return false
+		}
+
+		return true
+	}
+
+	t.Errorf("type of expected not handled, got %T", expected)
+	return false
+}
+
+func testNullLiteral(t *testing.T, exp ast.Expression) bool {
+	_, ok := exp.(*ast.NullLiteral)
+	if !ok {
+		t.Errorf("exp not *ast.NullLiteral, got %T", exp)
+		return false
+	}
+	return true
+}
+
+func testLiteralExpression(t *testing.T, expr ast.Expression, expected any) bool {
+	switch v := expected.(type) {
+	case int:
+		return testNumberLiteral(t, expr, v)
+	case float64:
+		return testNumberLiteral(t, expr, v)
+	case string:
+		return testIdentifier(t, expr, v)
+	case bool:
+		return testBooleanLiteral(t, expr, v)
+	case nil:
+		return testNullLiteral(t, expr)
+	}
+	t.Errorf("type of exp not handled, got %T", expr)
+	return false
+}
+
+func testPrefixExpression(t *testing.T, expr ast.Expression, operator string, right any) bool {
+	prefix, ok := expr.(*ast.PrefixExpression)
+	if !ok {
+		t.Errorf("expr not *ast.PrefixExpression, got %T", expr)
+		return false
+	}
+	if prefix.Operator() != operator {
+		t.Errorf("prefix.Operator not %q, got %q", operator, prefix.Operator())
+		return false
+	}
+	if !testLiteralExpression(t, prefix.Right, right) {
+		return false
+	}
+	return true
+}
+
+func testInfixExpression(t *testing.T, expr ast.Expression, left any, operator token.Type, right any) bool {
+	switch infix := expr.(type) {
+	case *ast.InfixExpression:
+		{
+			if infix.Operator() != operator {
+				t.Errorf("infix.Operator not %q, got %q", operator, infix.Operator())
+				return false
+			}
+			if !testLiteralExpression(t, infix.Left, left) {
+				return false
+			}
+			if !testLiteralExpression(t, infix.Right, right) {
+				return false
+			}
+			return true
+		}
+	}
+
+	t.Errorf("expr not *ast.InfixExpression, got %T", expr)
+	return false
+}
+
+func TestCallExpression(t *testing.T) {
+	type TestCase struct {
+		input  string
+		fnName string
+		args   []string
+	}
+
+	inputs := []TestCase{
+		{"hello()", "hello", []string{}},
+		{"hello(123)", "hello", []string{"123"}},
+		{`hello(123, 0.456)`, "hello", []string{"123", "0.456"}},
+		// {`hello(123, x + y, x * y)`, "hello", []string{"123", "(x + y)", "(x * y)"}},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		testCallExpression(t, expr, input.fnName, input.args)
+	}
+}
+
+func testCallExpression(t *testing.T, expr ast.Expression, fnName string, args []string) bool {
+	call, ok := expr.(*ast.CallExpression)
+	if !ok {
+		t.Errorf("expr not *ast.CallExpression, got %T", expr)
+		return false
+	}
+	if call.Fn.String() != fnName {
+		t.Errorf("call.Function.Value not %q, got %q", fnName, call.Fn.String())
+		return false
+	}
+
+	if len(call.Arguments) != len(args) {
+		t.Errorf("len(call.Arguments) not %d, got %d", len(args), len(call.Arguments))
+		return false
+	}
+	for i, arg := range args {
+		expected := call.Arguments[i].String()
+		if arg != expected {
+			t.Errorf("call.Arguments[%d].String() not %q, got %q", i, arg, expected)
+			return false
+		}
+	}
+	return true
+}
+
+func TestCaseWhenComments(t *testing.T) {
+	input := "CASE /* first */ WHEN a THEN 1 /* second */ WHEN b THEN 2 -- third\n -- more\n WHEN c THEN 3 WHEN d /* not a branch */ THEN 4 END"
+	p := New(lexer.NewWithOptions(input, lexer.Options{AllowComments: true}))
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	c, ok := expr.(*ast.CaseWhenExpression)
+	if !ok {
+		t.Fatalf("expr not *ast.CaseWhenExpression, got %T", expr)
+	}
+
+	expected := []string{"/* first */", "/* second */", "-- third\n-- more", ""}
+	if len(c.Whens) != len(expected) {
+		t.Fatalf("len(c.Whens) not %d, got %d", len(expected), len(c.Whens))
+	}
+	for i, when := range c.Whens {
+		if when.Comment != expected[i] {
+			t.Errorf("c.Whens[%d].Comment not %q, got %q", i, expected[i], when.Comment)
+		}
+	}
+	if str := "CASE WHEN a THEN 1 WHEN b THEN 2 WHEN c THEN 3 WHEN d THEN 4 END"; expr.String() != str {
+		t.Errorf("expr.String() not %q, got %q", str, expr.String())
+	}
+
+	// The comments are kept by Clone
+	if comment := ast.Clone(expr).(*ast.CaseWhenExpression).Whens[1].Comment; comment != "/* second */" {
+		t.Errorf("cloned comment not %q, got %q", "/* second */", comment)
+	}
+
+	// Comments are illegal by default
+	if _, err := parseExpressionWithError(t, "CASE /* first */ WHEN a THEN 1 END"); err == nil {
+		t.Errorf("comments should be parsed with error by default")
+	}
+}
+
+func TestMergedTokenComments(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	// Comments before or after NOT don't split the merged token
+	inputs := []TestCase{
+		{"x IS /*c*/ NOT NULL", "(x IS NOT NULL)"},
+		{"x IS NOT /*c*/ NULL", "(x IS NOT NULL)"},
+		{"x NOT /*c*/ IN (1)", "(x NOT IN 1)"},
+		{"x NOT IN /*c*/ (1)", "(x NOT IN 1)"},
+		{"x NOT -- c\n BETWEEN 1 AND 2", "(x NOT BETWEEN (1 AND 2))"},
+	}
+	for _, input := range inputs {
+		p := New(lexer.NewWithOptions(input.input, lexer.Options{AllowComments: true}))
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+}
+
+func TestCaseWhenExpression(t *testing.T) {
+	type WhenCase struct {
+		condition string
+		result    string
+	}
+
+	type TestCase struct {
+		input      string
+		whens      []WhenCase
+		elseResult string
+	}
+
+	inputs := []TestCase{
+		{
+			"CASE WHEN x > 0 THEN 1 WHEN x < 0 THEN -1 ELSE 0 END",
+			[]WhenCase{
+				{"(x > 0)", "1"},
+				{"(x < 0)", "(-1)"},
+			},
 			"0",
 		},
 	}
@@ -437,27 +1119,2992 @@ func TestCaseWhenExpression(t *testing.T) {
 			t.Errorf("expr not *ast.CaseWhenExpression, got %T", expr)
 			continue
 		}
-		if len(v.Whens) != len(input.whens) {
-			t.Errorf("len(v.Whens) not %d, got %d", len(input.whens), len(v.Whens))
+		if len(v.Whens) != len(input.whens) {
+			t.Errorf("len(v.Whens) not %d, got %d", len(input.whens), len(v.Whens))
+			continue
+		}
+
+		for i, when := range input.whens {
+			cond := v.Whens[i].Cond.String()
+			if cond != when.condition {
+				t.Errorf("v.Whens[%d].Cond.String() not %q, got %q", i, when.condition, cond)
+			}
+			then := v.Whens[i].Then.String()
+			if then != when.result {
+				t.Errorf("v.Whens[%d].Then.String() not %q, got %q", i, when.result, then)
+			}
+		}
+
+		if input.elseResult != "" {
+			elseStr := v.Else.String()
+			if elseStr != input.elseResult {
+				t.Errorf("v.Else.String() not %q, got %q", input.elseResult, elseStr)
+			}
+		}
+	}
+}
+
+func TestNestedCaseWhenExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{
+			"CASE WHEN CASE WHEN a THEN b END THEN c END",
+			"CASE WHEN CASE WHEN a THEN b END THEN c END",
+		},
+		{
+			"CASE WHEN a THEN CASE WHEN b THEN c ELSE d END ELSE e END",
+			"CASE WHEN a THEN CASE WHEN b THEN c ELSE d END ELSE e END",
+		},
+		{
+			"CASE WHEN a THEN b ELSE CASE WHEN c THEN d END END",
+			"CASE WHEN a THEN b ELSE CASE WHEN c THEN d END END",
+		},
+		{
+			"CASE WHEN CASE WHEN CASE WHEN a THEN b END THEN c END THEN CASE WHEN d THEN CASE WHEN e THEN f END END ELSE CASE WHEN g THEN h ELSE i END END",
+			"CASE WHEN CASE WHEN CASE WHEN a THEN b END THEN c END THEN CASE WHEN d THEN CASE WHEN e THEN f END END ELSE CASE WHEN g THEN h ELSE i END END",
+		},
+		{
+			"CASE WHEN a THEN 1 END + CASE WHEN b THEN 2 END",
+			"(CASE WHEN a THEN 1 END + CASE WHEN b THEN 2 END)",
+		},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.CaseWhenExpression)
+		if ok && v.TokenLiteral() != "CASE" {
+			t.Errorf("v.TokenLiteral() not %q, got %q", "CASE", v.TokenLiteral())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	errInputs := []TestCase{
+		{"CASE WHEN a THEN CASE WHEN b THEN c END END END", "1:45: unexpected END outside of CASE"},
+		{"CASE WHEN a THEN b END THEN c", "1:24: unexpected THEN outside of CASE"},
+		{"a ELSE b", "1:3: unexpected ELSE outside of CASE"},
+		{"CASE END", "1:6: CASE must have at least one WHEN"},
+		{"CASE x END", "1:8: CASE must have at least one WHEN"},
+		{"CASE x ELSE 1 END", "1:8: CASE must have at least one WHEN"},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if err.Error() != input.str {
+			t.Errorf("err.Error() not %q, got %q", input.str, err.Error())
+		}
+	}
+}
+
+func TestPositionExpression(t *testing.T) {
+	expr := parseExpression(t, "POSITION('a' IN name)")
+	v, ok := expr.(*ast.PositionExpression)
+	if !ok {
+		t.Fatalf("expr not *ast.PositionExpression, got %T", expr)
+	}
+	if v.Substr.String() != "'a'" {
+		t.Errorf("v.Substr.String() not %q, got %q", "'a'", v.Substr.String())
+	}
+	testIdentifier(t, v.Str, "name")
+
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"POSITION('a' IN name)", "POSITION('a' IN name)"},
+		{"position(a + 1 IN upper(name))", "POSITION((a + 1) IN upper(name))"},
+		{"POSITION('a' IN name) > 0", "(POSITION('a' IN name) > 0)"},
+		{"x IN (POSITION('a' IN name), 1)", "(x IN (POSITION('a' IN name), 1))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	// Generic call forms still work
+	testCallExpression(t, parseExpression(t, "position(a, b)"), "position", []string{"a", "b"})
+	testCallExpression(t, parseExpression(t, "POSITION(a AND b)"), "POSITION", []string{"(a AND b)"})
+	testCallExpression(t, parseExpression(t, "POSITION()"), "POSITION", []string{})
+
+	_, err := parseExpressionWithError(t, "POSITION('a' IN name, 1)")
+	if err == nil {
+		t.Errorf("should parsed error, but not")
+	}
+}
+
+func TestTrimExpression(t *testing.T) {
+	type TestCase struct {
+		input  string
+		spec   string
+		chars  string
+		source string
+		str    string
+	}
+
+	inputs := []TestCase{
+		{"TRIM(LEADING 'x' FROM col)", "LEADING", "'x'", "col", "TRIM(LEADING 'x' FROM col)"},
+		{"trim(trailing ' ' from col)", "TRAILING", "' '", "col", "TRIM(TRAILING ' ' FROM col)"},
+		{"TRIM(BOTH FROM col)", "BOTH", "", "col", "TRIM(BOTH FROM col)"},
+		{"TRIM('x' FROM col)", "", "'x'", "col", "TRIM('x' FROM col)"},
+		{"TRIM(FROM col)", "", "", "col", "TRIM(FROM col)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.TrimExpression)
+		if !ok {
+			t.Errorf("expr not *ast.TrimExpression, got %T", expr)
+			continue
+		}
+		if v.Spec != input.spec {
+			t.Errorf("v.Spec not %q, got %q", input.spec, v.Spec)
+		}
+		if input.chars == "" && v.Chars != nil {
+			t.Errorf("v.Chars not nil, got %q", v.Chars.String())
+		} else if input.chars != "" && (v.Chars == nil || v.Chars.String() != input.chars) {
+			t.Errorf("v.Chars not %q, got %v", input.chars, v.Chars)
+		}
+		testIdentifier(t, v.Source, input.source)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	testCallExpression(t, parseExpression(t, "TRIM(col)"), "TRIM", []string{"col"})
+	testCallExpression(t, parseExpression(t, "TRIM(col, 'x')"), "TRIM", []string{"col", "'x'"})
+
+	errInputs := []string{
+		"TRIM(LEADING 'x' col)",
+		"TRIM(BOTH FROM)",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// FROM only ends an expression inside the constructs expecting it
+	errCases := []ErrorCase{
+		{"a = 1 FROM users", "1:7: unexpected FROM outside of TRIM, SUBSTRING or OVERLAY"},
+		{"f(a from b)", "1:5: unexpected from outside of TRIM, SUBSTRING or OVERLAY"},
+		{"TRIM('x' FROM col FROM t)", "1:19: unexpected FROM outside of TRIM, SUBSTRING or OVERLAY"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestSubstringExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"SUBSTRING(str FROM 2 FOR 3)", "SUBSTRING(str FROM 2 FOR 3)"},
+		{"substring(str from 2)", "SUBSTRING(str FROM 2)"},
+		{"SUBSTRING(str FOR 3)", "SUBSTRING(str FOR 3)"},
+		{"SUBSTRING(a + b FROM x * 2 FOR n - 1)", "SUBSTRING((a + b) FROM (x * 2) FOR (n - 1))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if _, ok := expr.(*ast.SubstringExpression); !ok {
+			t.Errorf("expr not *ast.SubstringExpression, got %T", expr)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "SUBSTRING(str FROM 2 FOR 3)").(*ast.SubstringExpression)
+	testIdentifier(t, expr.Source, "str")
+	testNumberLiteral(t, expr.From, 2)
+	testNumberLiteral(t, expr.For, 3)
+
+	testCallExpression(t, parseExpression(t, "SUBSTRING(str, 2, 3)"), "SUBSTRING", []string{"str", "2", "3"})
+	testCallExpression(t, parseExpression(t, "substring(str, 2)"), "substring", []string{"str", "2"})
+
+	errInputs := []string{
+		"SUBSTRING(str FROM 2, 3)",
+		"SUBSTRING(str FROM)",
+		"SUBSTRING(str FOR 3 FROM 2)",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// FOR only ends an expression inside the constructs expecting it
+	errCases := []ErrorCase{
+		{"x FOR y", "1:3: unexpected FOR outside of SUBSTRING or OVERLAY"},
+		{"f(x FOR y)", "1:5: unexpected FOR outside of SUBSTRING or OVERLAY"},
+		{"TRIM('x' FROM col FOR 2)", "1:19: unexpected FOR outside of SUBSTRING or OVERLAY"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestIntervalExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		unit  token.Type
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"INTERVAL 3 MONTH", token.MONTH, "INTERVAL 3 MONTH"},
+		{"interval n day", token.DAY, "INTERVAL n DAY"},
+		{"INTERVAL n + 1 HOUR", token.HOUR, "INTERVAL (n + 1) HOUR"},
+		{"INTERVAL '1' YEAR", token.YEAR, "INTERVAL '1' YEAR"},
+		{"INTERVAL -2 WEEK", token.WEEK, "INTERVAL (-2) WEEK"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		interval, ok := expr.(*ast.IntervalExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.IntervalExpression, got %T", input.input, expr)
+			continue
+		}
+		if interval.Unit != input.unit {
+			t.Errorf("%q: unit not %q, got %q", input.input, input.unit, interval.Unit)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	for _, unit := range []string{"SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR"} {
+		expr := parseExpression(t, "INTERVAL 1 "+unit)
+		if !expr.(*ast.IntervalExpression).Unit.IsTimeUnit() {
+			t.Errorf("INTERVAL 1 %s: unit not a time unit", unit)
+		}
+	}
+
+	testCallExpression(t, parseExpression(t, "DATE_SUB('2023-01-15', INTERVAL 3 MONTH)"), "DATE_SUB", []string{"'2023-01-15'", "INTERVAL 3 MONTH"})
+	if expr := parseExpression(t, "d + INTERVAL 1 DAY > now()"); expr.String() != "((d + INTERVAL 1 DAY) > now())" {
+		t.Errorf("expr.String() not %q, got %q", "((d + INTERVAL 1 DAY) > now())", expr.String())
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"INTERVAL 3", `1:11: expected a time unit like DAY or MONTH after INTERVAL 3, got "EOF" instead`},
+		{"INTERVAL 3 days", ""},
+		{"INTERVAL 3 CENTURY", ""},
+		{"INTERVAL 3 + 1", `1:15: expected a time unit like DAY or MONTH after INTERVAL (3 + 1), got "EOF" instead`},
+		{"f(INTERVAL 3, 1)", `1:13: expected a time unit like DAY or MONTH after INTERVAL 3, got "," instead`},
+		{"INTERVAL DAY", ""},
+		{"INTERVAL", ""},
+		// Time units only end the quantity of INTERVAL
+		{"a YEAR", "1:3: unexpected YEAR outside of INTERVAL"},
+		{"f(x day)", "1:5: unexpected day outside of INTERVAL"},
+		{"INTERVAL 1 DAY HOUR", "1:16: unexpected HOUR outside of INTERVAL"},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestEscapeExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"name LIKE '100!%' ESCAPE '!'", "(name LIKE '100!%' ESCAPE '!')"},
+		{"name not like 'a#_%' escape '#'", "(name NOT LIKE 'a#_%' ESCAPE '#')"},
+		{"name LIKE p ESCAPE e", "(name LIKE p ESCAPE e)"},
+		{"name LIKE 'é%' ESCAPE 'é'", "(name LIKE 'é%' ESCAPE 'é')"},
+		{"name LIKE 'x''%' ESCAPE ''''", "(name LIKE 'x''%' ESCAPE '''')"},
+		{"a LIKE 'x' ESCAPE '!' AND b", "((a LIKE 'x' ESCAPE '!') AND b)"},
+		{"a LIKE 'x' + y ESCAPE '!'", "(a LIKE ('x' + y) ESCAPE '!')"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	infix := parseExpression(t, "name LIKE '100!%' ESCAPE '!'").(*ast.InfixExpression)
+	escape, ok := infix.Right.(*ast.EscapeExpression)
+	if !ok {
+		t.Fatalf("infix.Right not *ast.EscapeExpression, got %T", infix.Right)
+	}
+	if escape.Pattern.String() != "'100!%'" || escape.Escape.String() != "'!'" {
+		t.Errorf("escape wrong, got pattern %s and escape %s", escape.Pattern, escape.Escape)
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"name LIKE 'x' ESCAPE '!!'", "1:22: ESCAPE must be a single character, got '!!'"},
+		{"name LIKE 'x' ESCAPE ''", "1:22: ESCAPE must be a single character, got ''"},
+		{"SUBSTRING(s SIMILAR 'x' ESCAPE '##')", "1:32: ESCAPE must be a single character, got '##'"},
+		{"name LIKE 'x' ESCAPE", ""},
+		{"name LIKE 'x' ESCAPE )", ""},
+		// Only LIKE and SUBSTRING SIMILAR take an ESCAPE clause
+		{"x ESCAPE 'y'", "1:3: unexpected ESCAPE outside of LIKE or SUBSTRING"},
+		{"a = 'x' ESCAPE '!'", "1:9: unexpected ESCAPE outside of LIKE or SUBSTRING"},
+		{"name LIKE 'x' ESCAPE '!' ESCAPE '#'", "1:26: unexpected ESCAPE outside of LIKE or SUBSTRING"},
+		{"f(x ESCAPE '!')", "1:5: unexpected ESCAPE outside of LIKE or SUBSTRING"},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestExtractExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		field token.Type
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"EXTRACT(YEAR FROM '2023-05-15 14:30:00')", token.YEAR, "EXTRACT(YEAR FROM '2023-05-15 14:30:00')"},
+		{"extract(month from created_at)", token.MONTH, "EXTRACT(MONTH FROM created_at)"},
+		{"EXTRACT(DAY FROM d + INTERVAL 1 DAY)", token.DAY, "EXTRACT(DAY FROM (d + INTERVAL 1 DAY))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		extract, ok := expr.(*ast.ExtractExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.ExtractExpression, got %T", input.input, expr)
+			continue
+		}
+		if extract.Field != input.field {
+			t.Errorf("%q: field not %q, got %q", input.input, input.field, extract.Field)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	// Not a time unit first, a generic call
+	testCallExpression(t, parseExpression(t, "extract(s, '[0-9]+')"), "extract", []string{"s", "'[0-9]+'"})
+	testCallExpression(t, parseExpression(t, "EXTRACT()"), "EXTRACT", []string{})
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"EXTRACT(YEAR d)", `1:14: expected FROM after EXTRACT(YEAR, got "d" instead`},
+		{"EXTRACT(MONTH)", `1:14: expected FROM after EXTRACT(MONTH, got ")" instead`},
+		{"EXTRACT(DAY FROM)", ""},
+		{"EXTRACT(DAY FROM d", ""},
+		{"EXTRACT(DAY, d)", ""},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestSubstringSimilarExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{`SUBSTRING(str SIMILAR '%#"o_b#"%' ESCAPE '#')`, `SUBSTRING(str SIMILAR '%#"o_b#"%' ESCAPE '#')`},
+		{"substring(str similar p escape '!')", "SUBSTRING(str SIMILAR p ESCAPE '!')"},
+		{"SUBSTRING(a + b SIMILAR f(x) ESCAPE e)", "SUBSTRING((a + b) SIMILAR f(x) ESCAPE e)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if _, ok := expr.(*ast.SubstringSimilarExpression); !ok {
+			t.Errorf("expr not *ast.SubstringSimilarExpression, got %T", expr)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "SUBSTRING(str SIMILAR 'a%' ESCAPE '#')").(*ast.SubstringSimilarExpression)
+	testIdentifier(t, expr.Source, "str")
+	if expr.Pattern.String() != "'a%'" {
+		t.Errorf("expr.Pattern not %q, got %q", "'a%'", expr.Pattern.String())
+	}
+	if expr.Escape.String() != "'#'" {
+		t.Errorf("expr.Escape not %q, got %q", "'#'", expr.Escape.String())
+	}
+
+	// The FROM and comma forms are unaffected
+	if _, ok := parseExpression(t, "SUBSTRING(str FROM 2 FOR 3)").(*ast.SubstringExpression); !ok {
+		t.Errorf("SUBSTRING(str FROM 2 FOR 3) should be an *ast.SubstringExpression")
+	}
+	testCallExpression(t, parseExpression(t, "SUBSTRING(str, 'a%', '#')"), "SUBSTRING", []string{"str", "'a%'", "'#'"})
+
+	errInputs := []string{
+		"SUBSTRING(str SIMILAR 'a%')",
+		"SUBSTRING(str SIMILAR ESCAPE '#')",
+		"SUBSTRING(str SIMILAR 'a%' ESCAPE)",
+		"SUBSTRING(str SIMILAR 'a%' ESCAPE '#' FOR 2)",
+		"SUBSTRING(str FROM 2 SIMILAR 'a%' ESCAPE '#')",
+		"SUBSTRING(str, 'a%' SIMILAR '#')",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// SIMILAR only ends the source of SUBSTRING, there is no `SIMILAR TO` operator
+	errCases := []ErrorCase{
+		{"x SIMILAR y", "1:3: unexpected SIMILAR outside of SUBSTRING"},
+		{"x similar to 'a%'", "1:3: unexpected similar outside of SUBSTRING"},
+		{"SUBSTRING(str, 'a%' SIMILAR '#')", "1:21: unexpected SIMILAR outside of SUBSTRING"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestDottedIdentifiersAsName(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"a.b", "a.b"},
+		{"db.schema.t.col", "db.schema.t.col"},
+		{"t.amount * 2 > o.min", "((t.amount * 2) > o.min)"},
+		{"f(t.a, b)", "f(t.a, b)"},
+		{"a.b(x)", "a.b(x)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{DottedIdentifiersAsName: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	p := NewWithOptions(lexer.New("x + db.t.col"), Options{DottedIdentifiersAsName: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	ident := expr.(*ast.InfixExpression).Right
+	testIdentifier(t, ident, "db.t.col")
+	tok := ident.(*ast.Identifier).Token
+	if tok.Type != token.IDENT || tok.Literal != "db.t.col" || tok.Start != 4 || tok.End != 12 {
+		t.Errorf("token wrong, got %s [%d, %d)", tok, tok.Start, tok.End)
+	}
+
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrTestCase{
+		{"a.", `1:3: expected an identifier right after "a.", got "EOF" instead`},
+		{"a.b.", `1:5: expected an identifier right after "a.b.", got "EOF" instead`},
+		{"a. b", `1:4: expected an identifier right after "a.", got "IDENT" instead`},
+		{"a.'b'", `1:3: expected an identifier right after "a.", got "STRING" instead`},
+		{"a . b", ""},
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input.input), Options{DottedIdentifiersAsName: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+
+	// Without the option, a dotted name is an `ast.QualifiedIdentifier`
+	if expr, ok := parseExpression(t, "a.b").(*ast.QualifiedIdentifier); !ok {
+		t.Errorf("%q: expr not *ast.QualifiedIdentifier without DottedIdentifiersAsName, got %T", "a.b", expr)
+	}
+}
+
+func TestQualifiedIdentifier(t *testing.T) {
+	type TestCase struct {
+		input string
+		parts []string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"orders.amount", []string{"orders", "amount"}, "orders.amount"},
+		{"t . col", []string{"t", "col"}, "t.col"},
+		{"s.t.col", []string{"s", "t", "col"}, "s.t.col"},
+		{"db.schema.table.col", []string{"db", "schema", "table", "col"}, "db.schema.table.col"},
+		{"t.year", []string{"t", "year"}, "t.year"},
+		{"s.Order", []string{"s", "Order"}, "s.Order"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		v, ok := expr.(*ast.QualifiedIdentifier)
+		if !ok {
+			t.Errorf("%q: expr not *ast.QualifiedIdentifier, got %T", input.input, expr)
+			continue
+		}
+		if len(v.Parts) != len(input.parts) {
+			t.Errorf("%q: len(Parts) not %d, got %d", input.input, len(input.parts), len(v.Parts))
+			continue
+		}
+		for i, part := range input.parts {
+			testIdentifier(t, v.Parts[i], part)
+		}
+	}
+
+	type StrCase struct {
+		input string
+		str   string
+	}
+
+	strInputs := []StrCase{
+		{"t.amount * 2 > o.min", "((t.amount * 2) > o.min)"},
+		{"t.f(x)", "t.f(x)"},
+		{"s.t.f(x, y.z)", "s.t.f(x, y.z)"},
+		{"t.arr[1]", "t.arr[1]"},
+		{"t.arr[i.j]", "t.arr[i.j]"},
+		{"-t.col", "(-t.col)"},
+		{"t.col::int", "(t.col::int)"},
+		{"t.data -> 'a'", "(t.data -> 'a')"},
+	}
+	for _, input := range strInputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrorCase{
+		{"t.", `1:3: expected an identifier after "t.", got "EOF" instead`},
+		{"s.t.+", `1:5: expected an identifier after "s.t.", got "+" instead`},
+		{"t.'col'", `1:3: expected an identifier after "t.", got "STRING" instead`},
+		{"t.(x)", `1:3: expected an identifier after "t.", got "(" instead`},
+		{"1 .x", ""},
+		{"f(x).y", ""},
+		{".x", ""},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestWildcard(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"*", "*"},
+		{"users.*", "users.*"},
+		{"COUNT(*)", "COUNT(*)"},
+		{"COUNT(t.*)", "COUNT(t.*)"},
+		{"a * b", "(a * b)"},
+		{"a*b", "(a * b)"},
+		{"t.* IS NOT NULL", "(t.* IS NOT NULL)"},
+		{"COUNT(*) * 2", "(COUNT(*) * 2)"},
+		{"t.a * t.b", "(t.a * t.b)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	if _, ok := parseExpression(t, "*").(*ast.Wildcard); !ok {
+		t.Errorf("* not *ast.Wildcard")
+	}
+	wildcard, ok := parseExpression(t, "users.*").(*ast.QualifiedWildcard)
+	if !ok {
+		t.Fatalf("users.* not *ast.QualifiedWildcard")
+	}
+	testIdentifier(t, wildcard.Table, "users")
+	testInfixExpression(t, parseExpression(t, "a * b"), "a", "*", "b")
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"s.t.*", `1:5: expected a table name before ".*", got "s.t"`},
+		{"t.*.a", ""},
+		{"a *", ""},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestArrayLiteral(t *testing.T) {
+	type TestCase struct {
+		input    string
+		elements []string
+		str      string
+	}
+
+	inputs := []TestCase{
+		{"[1, 2, 3]", []string{"1", "2", "3"}, "[1, 2, 3]"},
+		{"[]", nil, "[]"},
+		{"[x]", []string{"x"}, "[x]"},
+		{"[1, 'a', x, NULL]", []string{"1", "'a'", "x", "NULL"}, "[1, 'a', x, NULL]"},
+		{"[[1], [2, 3], []]", []string{"[1]", "[2, 3]", "[]"}, "[[1], [2, 3], []]"},
+		{"[a + 1, f(b), (c)]", []string{"(a + 1)", "f(b)", "c"}, "[(a + 1), f(b), c]"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		array, ok := expr.(*ast.ArrayLiteral)
+		if !ok {
+			t.Errorf("expr not *ast.ArrayLiteral, got %T", expr)
+			continue
+		}
+		if len(array.Elements) != len(input.elements) {
+			t.Errorf("%q: len(array.Elements) not %d, got %d", input.input, len(input.elements), len(array.Elements))
+			continue
+		}
+		for i, element := range array.Elements {
+			if element.String() != input.elements[i] {
+				t.Errorf("array.Elements[%d] not %q, got %q", i, input.elements[i], element.String())
+			}
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	testCallExpression(t, parseExpression(t, "has([1, 2], x)"), "has", []string{"[1, 2]", "x"})
+	if expr := parseExpression(t, "x IN [1, 2]"); expr.String() != "(x IN [1, 2])" {
+		t.Errorf("expr.String() not %q, got %q", "(x IN [1, 2])", expr.String())
+	}
+
+	errInputs := []string{
+		"[1, 2",
+		"[1 2]",
+		"[1, ]",
+		"[, 1]",
+		"]",
+		"[1)",
+	}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	p := NewWithOptions(lexer.New("[1, 2, 3]"), Options{MaxListElements: 2})
+	if _, err := p.ParseExpression(); err == nil {
+		t.Errorf("array elements should be limited by MaxListElements")
+	}
+}
+
+func TestTypedArrayLiteral(t *testing.T) {
+	type TestCase struct {
+		input    string
+		elements []string
+		str      string
+	}
+
+	inputs := []TestCase{
+		{"ARRAY[1, 2, 3]", []string{"1", "2", "3"}, "ARRAY[1, 2, 3]"},
+		{"array[x]", []string{"x"}, "ARRAY[x]"},
+		{"ARRAY []", nil, "ARRAY[]"},
+		{"ARRAY[ARRAY[1], [2]]", []string{"ARRAY[1]", "[2]"}, "ARRAY[ARRAY[1], [2]]"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		array, ok := expr.(*ast.ArrayLiteral)
+		if !ok {
+			t.Errorf("expr not *ast.ArrayLiteral, got %T", expr)
+			continue
+		}
+		if !array.Typed {
+			t.Errorf("%q: array.Typed not true", input.input)
+		}
+		if len(array.Elements) != len(input.elements) {
+			t.Errorf("%q: len(array.Elements) not %d, got %d", input.input, len(input.elements), len(array.Elements))
+			continue
+		}
+		for i, element := range array.Elements {
+			if element.String() != input.elements[i] {
+				t.Errorf("array.Elements[%d] not %q, got %q", i, input.elements[i], element.String())
+			}
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	castInputs := map[string]string{
+		"ARRAY[]::int[]":          "(ARRAY[]::int[])",
+		"ARRAY[1, 2]::bigint[]":   "(ARRAY[1, 2]::bigint[])",
+		"x::text[][]":             "(x::text[][])",
+		"x::int[][1]":             "(x::int[])[1]",
+		"CAST(ARRAY[] AS int[])":  "CAST(ARRAY[] AS int[])",
+		"ARRAY[1] = ARRAY[]::int": "(ARRAY[1] = (ARRAY[]::int))",
+		"ARRAY[1, 2][1]":          "ARRAY[1, 2][1]",
+		"array":                   "array",
+	}
+	for input, str := range castInputs {
+		if expr := parseExpression(t, input); expr.String() != str {
+			t.Errorf("%q: expr.String() not %q, got %q", input, str, expr.String())
+		}
+	}
+
+	errInputs := []string{
+		"ARRAY[1, 2",
+		"ARRAY[1,]",
+		"x::int[",
+		"CAST(x AS int[1])",
+	}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
+	}
+}
+
+func TestLambdaExpression(t *testing.T) {
+	type TestCase struct {
+		input  string
+		params []string
+		body   string
+		str    string
+	}
+
+	inputs := []TestCase{
+		{"x -> x > 1", []string{"x"}, "(x > 1)", "x -> (x > 1)"},
+		{"(x, y) -> x + y", []string{"x", "y"}, "(x + y)", "(x, y) -> (x + y)"},
+		{"(x) -> x", []string{"x"}, "x", "x -> x"},
+		{"x -> x > 1 AND x < 5", []string{"x"}, "((x > 1) AND (x < 5))", "x -> ((x > 1) AND (x < 5))"},
+		{"(a, b, c) -> f(a, b)[c]", []string{"a", "b", "c"}, "f(a, b)[c]", "(a, b, c) -> f(a, b)[c]"},
+		{"x -> y -> x + y", []string{"x"}, "y -> (x + y)", "x -> y -> (x + y)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{Lambdas: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		lambda, ok := expr.(*ast.LambdaExpression)
+		if !ok {
+			t.Errorf("expr not *ast.LambdaExpression, got %T", expr)
+			continue
+		}
+		if len(lambda.Params) != len(input.params) {
+			t.Errorf("len(lambda.Params) not %d, got %d", len(input.params), len(lambda.Params))
+			continue
+		}
+		for i, param := range lambda.Params {
+			testIdentifier(t, param, input.params[i])
+		}
+		if lambda.Body.String() != input.body {
+			t.Errorf("lambda.Body not %q, got %q", input.body, lambda.Body.String())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	calls := map[string]string{
+		"arrayFilter(x -> x > 1, [1, 2, 3])[1]":    "arrayFilter(x -> (x > 1), [1, 2, 3])[1]",
+		"arrayMap((k, v) -> concat(k, v), ks, vs)": "arrayMap((k, v) -> concat(k, v), ks, vs)",
+	}
+	for input, str := range calls {
+		p := NewWithOptions(lexer.New(input), Options{Lambdas: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input, err)
+			continue
+		}
+		if expr.String() != str {
+			t.Errorf("expr.String() not %q, got %q", str, expr.String())
+		}
+	}
+
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrTestCase{
+		{"1 -> x", "1:3: lambda parameters must be identifiers, got 1"},
+		{"(x, 1) -> x", "1:8: lambda parameters must be identifiers, got 1"},
+		{"f(x) -> x", "1:6: lambda parameters must be identifiers, got f(x)"},
+		{"a + b -> b", "1:7: lambda parameters must be identifiers, got (a + b)"},
+		{"(x, X) -> x", "1:5: duplicate lambda parameter X"},
+		{"x ->", ""},
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input.input), Options{Lambdas: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+
+	// `->` is the JSON access operator without the option
+	expr := parseExpression(t, "x -> 'a'")
+	if _, ok := expr.(*ast.LambdaExpression); ok {
+		t.Errorf("x -> 'a' should not be an *ast.LambdaExpression without Lambdas")
+	}
+
+	p := NewWithOptions(lexer.New("arrayFilter((x, y) -> x > y, a, b)"), Options{Lambdas: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	expected := map[ast.RenderOptions]string{
+		{Parentheses: ast.ParenthesesMinimal}: "arrayFilter((x, y) -> x > y, a, b)",
+		{}:                                    "arrayFilter(((x, y) -> (x > y)), a, b)",
+	}
+	for opts, str := range expected {
+		if actual := ast.Render(expr, opts); actual != str {
+			t.Errorf("Render() not %q, got %q", str, actual)
+		}
+	}
+}
+
+func TestIndexExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		left  string
+		index string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"a[1]", "a", "1", "a[1]"},
+		{"m['k']", "m", "'k'", "m['k']"},
+		{"a[1][2]", "a[1]", "2", "a[1][2]"},
+		{"a[i + 1]", "a", "(i + 1)", "a[(i + 1)]"},
+		{"f(x)[1]", "f(x)", "1", "f(x)[1]"},
+		{"arrayFilter(x, [1, 2, 3])[1]", "arrayFilter(x, [1, 2, 3])", "1", "arrayFilter(x, [1, 2, 3])[1]"},
+		{"[1, 2, 3][2]", "[1, 2, 3]", "2", "[1, 2, 3][2]"},
+		{"(a + b)[1]", "(a + b)", "1", "(a + b)[1]"},
+		{"m[a[0]]", "m", "a[0]", "m[a[0]]"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		index, ok := expr.(*ast.IndexExpression)
+		if !ok {
+			t.Errorf("expr not *ast.IndexExpression, got %T", expr)
+			continue
+		}
+		if index.Left.String() != input.left {
+			t.Errorf("index.Left not %q, got %q", input.left, index.Left.String())
+		}
+		if index.Index.String() != input.index {
+			t.Errorf("index.Index not %q, got %q", input.index, index.Index.String())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	precedenceInputs := map[string]string{
+		"-a[1]":        "(-a[1])",
+		"a[1] + b[2]":  "(a[1] + b[2])",
+		"m['k'] = 'v'": "(m['k'] = 'v')",
+		"x::int[1]":    "(x::int)[1]",
+	}
+	for input, str := range precedenceInputs {
+		if expr := parseExpression(t, input); expr.String() != str {
+			t.Errorf("expr.String() not %q, got %q", str, expr.String())
+		}
+	}
+
+	errInputs := []string{
+		"a[]",
+		"a[1",
+		"a[1, 2]",
+		"a[1)",
+	}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// A `]` only ends an expression inside brackets
+	errCases := []ErrorCase{
+		{"a ] b", "1:3: unexpected ] outside of an array or subscript"},
+		{"a[1]]", "1:5: unexpected ] outside of an array or subscript"},
+		{"f(a])", "1:4: unexpected ] outside of an array or subscript"},
+		{"(a ])", "1:4: unexpected ] outside of an array or subscript"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestOverlayExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"OVERLAY('abc' PLACING 'X' FROM 2 FOR 1)", "OVERLAY('abc' PLACING 'X' FROM 2 FOR 1)"},
+		{"overlay(str placing 'X' from 2)", "OVERLAY(str PLACING 'X' FROM 2)"},
+		{"OVERLAY(a + b PLACING f(x) FROM n + 1 FOR len - 1)", "OVERLAY((a + b) PLACING f(x) FROM (n + 1) FOR (len - 1))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if _, ok := expr.(*ast.OverlayExpression); !ok {
+			t.Errorf("expr not *ast.OverlayExpression, got %T", expr)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "OVERLAY(str PLACING 'X' FROM 2 FOR 1)").(*ast.OverlayExpression)
+	testIdentifier(t, expr.Source, "str")
+	if expr.Placing.String() != "'X'" {
+		t.Errorf("expr.Placing not %q, got %q", "'X'", expr.Placing.String())
+	}
+	testNumberLiteral(t, expr.From, 2)
+	testNumberLiteral(t, expr.For, 1)
+
+	expr = parseExpression(t, "OVERLAY(str PLACING 'X' FROM 2)").(*ast.OverlayExpression)
+	if expr.For != nil {
+		t.Errorf("expr.For not nil, got %s", expr.For)
+	}
+
+	testCallExpression(t, parseExpression(t, "OVERLAY('abc', 'X', 2, 1)"), "OVERLAY", []string{"'abc'", "'X'", "2", "1"})
+	testCallExpression(t, parseExpression(t, "overlay(str, 'X', 2)"), "overlay", []string{"str", "'X'", "2"})
+
+	errInputs := []string{
+		"OVERLAY(str PLACING 'X')",
+		"OVERLAY(str PLACING 'X' FOR 1)",
+		"OVERLAY(str PLACING 'X' FROM)",
+		"OVERLAY(str PLACING 'X' FROM 2 FOR 1 FOR 2)",
+		"OVERLAY(str, 'X' PLACING 2)",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// PLACING only ends the source of OVERLAY
+	errCases := []ErrorCase{
+		{"x PLACING y", "1:3: unexpected PLACING outside of OVERLAY"},
+		{"OVERLAY(str PLACING 'X' PLACING 'Y' FROM 2)", "1:25: unexpected PLACING outside of OVERLAY"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestQuantifiedLikeExpression(t *testing.T) {
+	type TestCase struct {
+		input      string
+		operator   token.Type
+		quantifier token.Type
+		patterns   int
+		str        string
+	}
+
+	inputs := []TestCase{
+		{"col LIKE ANY ('a%', 'b%')", token.LIKE, token.ANY, 2, "(col LIKE ANY ('a%', 'b%'))"},
+		{"col like all ('a%')", token.LIKE, token.ALL, 1, "(col LIKE ALL ('a%'))"},
+		{"col LIKE SOME (x, 'b%', 'c%')", token.LIKE, token.SOME, 3, "(col LIKE SOME (x, 'b%', 'c%'))"},
+		{"col NOT LIKE ANY ('a%', 'b%')", token.NOT_LIKE, token.ANY, 2, "(col NOT LIKE ANY ('a%', 'b%'))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.LikeExpression)
+		if !ok {
+			t.Errorf("expr not *ast.LikeExpression, got %T", expr)
+			continue
+		}
+		testIdentifier(t, v.Left, "col")
+		if v.Operator() != input.operator {
+			t.Errorf("v.Operator() not %q, got %q", input.operator, v.Operator())
+		}
+		if v.Quantifier != input.quantifier {
+			t.Errorf("v.Quantifier not %q, got %q", input.quantifier, v.Quantifier)
+		}
+		if len(v.Patterns) != input.patterns {
+			t.Errorf("len(v.Patterns) not %d, got %d", input.patterns, len(v.Patterns))
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "col LIKE ANY ('a%') AND b")
+	if expr.String() != "((col LIKE ANY ('a%')) AND b)" {
+		t.Errorf("expr.String() not %q, got %q", "((col LIKE ANY ('a%')) AND b)", expr.String())
+	}
+
+	// Plain LIKE stays single-pattern
+	testInfixExpression(t, parseExpression(t, "col LIKE x"), "col", token.LIKE, "x")
+
+	errInputs := []string{
+		"col LIKE ANY 'a%'",
+		"col LIKE ALL ()",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+}
+
+func TestBitwiseNotExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"~~x", "(~(~x))"},
+		{"~x + 1", "((~x) + 1)"},
+		{"a * ~b", "(a * (~b))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	const message = "`~` is the prefix bitwise NOT operator and cannot be used between two expressions"
+	errInputs := map[string]string{"a ~ b": "1:3: " + message, "a + b ~ c": "1:7: " + message, "f(a ~ b)": "1:5: " + message}
+	for input, expected := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		} else if err.Error() != expected {
+			t.Errorf("err.Error() not %q, got %q", expected, err.Error())
+		}
+	}
+}
+
+func TestNormalizeKeywordCase(t *testing.T) {
+	const source = "case when x aNd y then True else null end"
+
+	type TestCase struct {
+		opts     Options
+		caseLit  string
+		andLit   string
+		trueLit  string
+		nullLit  string
+		identLit string
+	}
+
+	inputs := []TestCase{
+		{Options{}, "case", "aNd", "True", "null", "x"},
+		{Options{NormalizeKeywordCase: true}, "CASE", "AND", "TRUE", "NULL", "x"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(source), input.opts)
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression() failed: %s", err)
+		}
+
+		v := expr.(*ast.CaseWhenExpression)
+		cond := v.Whens[0].Cond.(*ast.InfixExpression)
+		literals := []string{
+			v.TokenLiteral(),
+			cond.TokenLiteral(),
+			v.Whens[0].Then.TokenLiteral(),
+			v.Else.TokenLiteral(),
+			cond.Left.TokenLiteral(),
+		}
+		expected := []string{input.caseLit, input.andLit, input.trueLit, input.nullLit, input.identLit}
+		for i := range expected {
+			if literals[i] != expected[i] {
+				t.Errorf("NormalizeKeywordCase=%t: literal not %q, got %q", input.opts.NormalizeKeywordCase, expected[i], literals[i])
+			}
+		}
+	}
+}
+
+func TestNotExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"NOT a", "(NOT a)"},
+		{"not not a", "(NOT (NOT a))"},
+		{"NOT a = b", "(NOT (a = b))"},
+		{"NOT a AND b", "((NOT a) AND b)"},
+		{"NOT a OR b", "((NOT a) OR b)"},
+		{"a AND NOT b", "(a AND (NOT b))"},
+		{"NOT a + 1 > b", "(NOT ((a + 1) > b))"},
+		{"NOT a IN (1, 2)", "(NOT (a IN (1, 2)))"},
+		{"NOT a LIKE 'x%'", "(NOT (a LIKE 'x%'))"},
+		{"NOT a BETWEEN 1 AND 2", "(NOT (a BETWEEN (1 AND 2)))"},
+		{"NOT (a AND b)", "(NOT (a AND b))"},
+		{"NOT a IS NULL", "(NOT (a IS NULL))"},
+		{"NOT active", "(NOT active)"},
+		{"NOT NOT x", "(NOT (NOT x))"},
+		{"NOT somefunc()", "(NOT somefunc())"},
+		{"x NOT BETWEEN a AND b", "(x NOT BETWEEN (a AND b))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr, ok := parseExpression(t, "NOT NOT x").(*ast.PrefixExpression)
+	if !ok || expr.Token.Type != token.NOT {
+		t.Fatalf("NOT NOT x not a NOT *ast.PrefixExpression, got %#v", expr)
+	}
+	inner, ok := expr.Right.(*ast.PrefixExpression)
+	if !ok || inner.Token.Type != token.NOT {
+		t.Fatalf("NOT x not a NOT *ast.PrefixExpression, got %#v", expr.Right)
+	}
+	testIdentifier(t, inner.Right, "x")
+
+	call, ok := parseExpression(t, "NOT somefunc()").(*ast.PrefixExpression)
+	if !ok {
+		t.Fatalf("NOT somefunc() not *ast.PrefixExpression, got %#v", call)
+	}
+	testCallExpression(t, call.Right, "somefunc", []string{})
+
+	between, ok := parseExpression(t, "x NOT BETWEEN a AND b").(*ast.NotBetweenExpression)
+	if !ok {
+		t.Fatalf("x NOT BETWEEN a AND b not *ast.NotBetweenExpression, got %#v", between)
+	}
+	testIdentifier(t, between.Left, "x")
+
+	errInputs := []string{"NOT", "NOT )", "a NOT"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	errCases := []ErrorCase{
+		{"x NOT", "1:3: `x NOT` is incomplete, expected IN, BETWEEN or LIKE after NOT"},
+		{"a + b not", "1:7: `b NOT` is incomplete, expected IN, BETWEEN or LIKE after NOT"},
+		{"x NOT somefunc()", "1:3: `NOT` can only prefix an expression like `NOT x` and cannot be used between two expressions"},
+	}
+	for _, input := range errCases {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestMaxListElements(t *testing.T) {
+	args := func(n int) string {
+		list := make([]string, n)
+		for i := range list {
+			list[i] = strconv.Itoa(i)
+		}
+		return strings.Join(list, ", ")
+	}
+
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"f(" + args(10) + ")", ""},
+		{"f(" + args(11) + ")", "1:33: too many list elements: the limit is 10"},
+		{"(" + args(10) + ")", ""},
+		{"x IN (" + args(11) + ")", "1:37: too many list elements: the limit is 10"},
+		{"f(g(" + args(10) + "), " + args(9) + ")", ""},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{MaxListElements: 10})
+		_, err := p.ParseExpression()
+		if input.err == "" && err != nil {
+			t.Errorf("ParseExpression() failed: %s", err)
+		} else if input.err != "" && (err == nil || err.Error() != input.err) {
+			t.Errorf("err not %q, got %v", input.err, err)
+		}
+	}
+
+	_, err := parseExpressionWithError(t, "f("+args(2000)+")")
+	if err != nil {
+		t.Errorf("unlimited by default, got %s", err)
+	}
+}
+
+func TestIsNormalizedExpression(t *testing.T) {
+	type TestCase struct {
+		input   string
+		form    string
+		negated bool
+		str     string
+	}
+
+	inputs := []TestCase{
+		{"x IS NORMALIZED", "", false, "(x IS NORMALIZED)"},
+		{"x is normalized nfc", "NFC", false, "(x IS NORMALIZED NFC)"},
+		{"x IS NOT NORMALIZED", "", true, "(x IS NOT NORMALIZED)"},
+		{"x IS NOT NORMALIZED NFKD", "NFKD", true, "(x IS NOT NORMALIZED NFKD)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.IsNormalizedExpression)
+		if !ok {
+			t.Errorf("expr not *ast.IsNormalizedExpression, got %T", expr)
+			continue
+		}
+		testIdentifier(t, v.Expr, "x")
+		if v.Form != input.form {
+			t.Errorf("v.Form not %q, got %q", input.form, v.Form)
+		}
+		if v.Negated != input.negated {
+			t.Errorf("v.Negated not %t, got %t", input.negated, v.Negated)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "x IS NORMALIZED AND nfc = 1")
+	expected := "((x IS NORMALIZED) AND (nfc = 1))"
+	if expr.String() != expected {
+		t.Errorf("expr.String() not %q, got %q", expected, expr.String())
+	}
+}
+
+func TestCastExpression(t *testing.T) {
+	type TestCase struct {
+		input  string
+		expr   string
+		typ    string
+		format string
+		str    string
+	}
+
+	inputs := []TestCase{
+		{"CAST(x AS INT)", "x", "INT", "", "CAST(x AS INT)"},
+		{"cast(a + b as DECIMAL(10, 2))", "(a + b)", "DECIMAL(10, 2)", "", "CAST((a + b) AS DECIMAL(10, 2))"},
+		{"CAST(x AS STRING FORMAT 'YYYY-MM-DD')", "x", "STRING", "'YYYY-MM-DD'", "CAST(x AS STRING FORMAT 'YYYY-MM-DD')"},
+		{"CAST(d AS DATE format 'MM/DD')", "d", "DATE", "'MM/DD'", "CAST(d AS DATE FORMAT 'MM/DD')"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.CastExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CastExpression, got %T", expr)
+			continue
+		}
+		if v.Expr.String() != input.expr {
+			t.Errorf("v.Expr.String() not %q, got %q", input.expr, v.Expr.String())
+		}
+		if v.Type.String() != input.typ {
+			t.Errorf("v.Type.String() not %q, got %q", input.typ, v.Type.String())
+		}
+		if input.format == "" && v.Format != nil {
+			t.Errorf("v.Format not nil, got %q", v.Format.String())
+		} else if input.format != "" && (v.Format == nil || v.Format.String() != input.format) {
+			t.Errorf("v.Format not %q, got %v", input.format, v.Format)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	testCallExpression(t, parseExpression(t, "FORMAT(x, 2)"), "FORMAT", []string{"x", "2"})
+
+	errInputs := []string{
+		"CAST(x)",
+		"CAST(x, INT)",
+		"CAST(x AS)",
+		"CAST(x AS 1)",
+		"CAST(x AS STRING FORMAT)",
+		"CAST(x AS STRING FORMAT y)",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	messageInputs := []ErrTestCase{
+		{"CAST(x)", `1:7: CAST requires AS before the target type, got ")"`},
+		{"CAST(x AS)", `1:10: CAST requires a target type after AS: expected type name, got ")"`},
+		{"CAST(a + b AS 'INT')", `1:15: CAST requires a target type after AS: expected type name, got "STRING"`},
+	}
+	for _, input := range messageInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	// The type takes arguments like a call, and the result parses back to the same tree
+	for _, input := range []string{"CAST(order_amount AS DECIMAL(10, 2))", "Cast(a+b as decimal(10,2))"} {
+		expr := parseExpression(t, input)
+		typ := expr.(*ast.CastExpression).Type
+		if len(typ.Args) != 2 {
+			t.Fatalf("%q: len(typ.Args) not 2, got %d", input, len(typ.Args))
+		}
+		testNumberLiteral(t, typ.Args[0], 10)
+		testNumberLiteral(t, typ.Args[1], 2)
+
+		if d := ast.Diff(expr, parseExpression(t, expr.String())); d != "" {
+			t.Errorf("%q does not round-trip: %s", input, d)
+		}
+	}
+}
+
+func TestDoubleColonCast(t *testing.T) {
+	type TestCase struct {
+		input string
+		expr  string
+		typ   string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"1::int", "1", "int", "(1::int)"},
+		{"1::int::int", "(1::int)", "int", "((1::int)::int)"},
+		{"1::int::text", "(1::int)", "text", "((1::int)::text)"},
+		{"x::DECIMAL(10, 2)", "x", "DECIMAL(10, 2)", "(x::DECIMAL(10, 2))"},
+		{"(a + b)::text", "(a + b)", "text", "((a + b)::text)"},
+		{"f(x)::\"my type\"", "f(x)", `"my type"`, `(f(x)::"my type")`},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.CastExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CastExpression, got %T", expr)
+			continue
+		}
+		if !v.IsDoubleColon() {
+			t.Errorf("%q: v.IsDoubleColon() not true", input.input)
+		}
+		if v.Expr.String() != input.expr {
+			t.Errorf("v.Expr.String() not %q, got %q", input.expr, v.Expr.String())
+		}
+		if v.Type.String() != input.typ {
+			t.Errorf("v.Type.String() not %q, got %q", input.typ, v.Type.String())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	precedenceInputs := []TestCase{
+		{"-1::int", "", "", "(-(1::int))"},
+		{"a + b::int", "", "", "(a + (b::int))"},
+		{"x::int = 1", "", "", "((x::int) = 1)"},
+		{"'a'::text COLLATE c", "", "", "(('a'::text) COLLATE c)"},
+	}
+	for _, input := range precedenceInputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	if parseExpression(t, "CAST(x AS INT)").(*ast.CastExpression).IsDoubleColon() {
+		t.Errorf("CAST(x AS INT) should not be a :: cast")
+	}
+
+	errInputs := []string{
+		"1::",
+		"1::2",
+		"::int",
+		"1::int(",
+		"1::int()",
+		"1:int",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+}
+
+func TestCustomOperatorExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"a OPERATOR(pg_catalog.+) b", "(a OPERATOR(pg_catalog.+) b)"},
+		{"a operator(+) b", "(a OPERATOR(+) b)"},
+		{"a OPERATOR(s.t.||) b", "(a OPERATOR(s.t.||) b)"},
+		{"a OPERATOR(pg_catalog.=) b + 1 = c", "((a OPERATOR(pg_catalog.=) (b + 1)) = c)"},
+		{"a * b OPERATOR(pg_catalog.-) c", "((a * b) OPERATOR(pg_catalog.-) c)"},
+		{"operator + 1", "(operator + 1)"},
+		{"operator(1)", "operator(1)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{PostgresOperators: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "operator = 1")
+	testInfixExpression(t, expr, "operator", token.EQ, 1)
+
+	errInputs := []string{
+		"a OPERATOR b",
+		"a OPERATOR() b",
+		"a OPERATOR(pg_catalog) b",
+		"a OPERATOR(pg_catalog.+ b",
+		"a OPERATOR(pg_catalog.+)",
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input), Options{PostgresOperators: true})
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	if _, err := parseExpressionWithError(t, "a OPERATOR(pg_catalog.+) b"); err == nil {
+		t.Errorf("OPERATOR() should be rejected without the PostgresOperators option")
+	}
+}
+
+func TestWithinGroup(t *testing.T) {
+	type TestCase struct {
+		input string
+		items []string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY x)", []string{"x"}, "PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY x)"},
+		{"percentile_disc(0.9) within group (order by a + b desc)", []string{"(a + b) DESC"}, "percentile_disc(0.9) WITHIN GROUP (ORDER BY (a + b) DESC)"},
+		{"mode() WITHIN GROUP (ORDER BY x ASC, y) > 1", []string{"x ASC", "y"}, "(mode() WITHIN GROUP (ORDER BY x ASC, y) > 1)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{OrderedSetAggregates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+
+		if infix, ok := expr.(*ast.InfixExpression); ok {
+			expr = infix.Left
+		}
+		call, ok := expr.(*ast.CallExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CallExpression, got %T", expr)
+			continue
+		}
+		if len(call.WithinGroup) != len(input.items) {
+			t.Errorf("len(call.WithinGroup) not %d, got %d", len(input.items), len(call.WithinGroup))
+			continue
+		}
+		for i, item := range call.WithinGroup {
+			if item.String() != input.items[i] {
+				t.Errorf("call.WithinGroup[%d] not %q, got %q", i, input.items[i], item.String())
+			}
+		}
+	}
+
+	errInputs := []string{
+		"f(x) WITHIN GROUP",
+		"f(x) WITHIN (ORDER BY x)",
+		"f(x) WITHIN GROUP (x)",
+		"f(x) WITHIN GROUP (ORDER BY)",
+		"f(x) WITHIN GROUP (ORDER BY x",
+		"f(x) WITHIN GROUP (ORDER BY x,)",
+		"f(x) WITHIN GROUP (ORDER BY x DESC ASC)",
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input), Options{OrderedSetAggregates: true})
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	if _, err := parseExpressionWithError(t, "PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY x)"); err == nil {
+		t.Errorf("WITHIN GROUP should be rejected without the OrderedSetAggregates option")
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// ASC and DESC only end the items of ORDER BY
+	errCases := []ErrorCase{
+		{"a DESC", "1:3: unexpected DESC outside of ORDER BY"},
+		{"a asc", "1:3: unexpected asc outside of ORDER BY"},
+		{"f(a DESC)", "1:5: unexpected DESC outside of ORDER BY"},
+	}
+	for _, input := range errCases {
+		p := NewWithOptions(lexer.New(input.input), Options{OrderedSetAggregates: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+
+	// The keywords keep the input casing, and are identifiers or denied without the option
+	p := NewWithOptions(lexer.New("f(x) Within Group (Order By x Desc)"), Options{OrderedSetAggregates: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	if item := expr.(*ast.CallExpression).WithinGroup[0]; item.Direction != token.DESC {
+		t.Errorf("item.Direction not %q, got %q", token.DESC, item.Direction)
+	}
+	if expr := parseExpression(t, "within + 1"); expr.String() != "(within + 1)" {
+		t.Errorf("expr.String() not %q, got %q", "(within + 1)", expr.String())
+	}
+}
+
+func TestAggregateOrderBy(t *testing.T) {
+	type TestCase struct {
+		input string
+		args  int
+		items []string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"ARRAY_AGG(x ORDER BY y)", 1, []string{"y"}, "ARRAY_AGG(x ORDER BY y)"},
+		{"string_agg(name, ', ' order by id desc, name)", 2, []string{"id DESC", "name"}, "string_agg(name, ', ' ORDER BY id DESC, name)"},
+		{"ARRAY_AGG(DISTINCT x)", 1, nil, "ARRAY_AGG(DISTINCT x)"},
+		// DISTINCT is the aggregate modifier, not a prefix of the first argument
+		{"ARRAY_AGG(DISTINCT x ORDER BY y)", 1, []string{"y"}, "ARRAY_AGG(DISTINCT x ORDER BY y)"},
+		{"ARRAY_AGG(DISTINCT x ORDER BY x ASC) = y", 1, []string{"x ASC"}, "(ARRAY_AGG(DISTINCT x ORDER BY x ASC) = y)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{OrderedSetAggregates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+
+		if infix, ok := expr.(*ast.InfixExpression); ok {
+			expr = infix.Left
+		}
+		call, ok := expr.(*ast.CallExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CallExpression, got %T", expr)
+			continue
+		}
+		if len(call.Arguments) != input.args {
+			t.Errorf("len(call.Arguments) not %d, got %d", input.args, len(call.Arguments))
+		}
+		if len(call.OrderBy) != len(input.items) {
+			t.Errorf("len(call.OrderBy) not %d, got %d", len(input.items), len(call.OrderBy))
+			continue
+		}
+		for i, item := range call.OrderBy {
+			if item.String() != input.items[i] {
+				t.Errorf("call.OrderBy[%d] not %q, got %q", i, input.items[i], item.String())
+			}
+		}
+	}
+
+	errInputs := []string{
+		"ARRAY_AGG(x ORDER y)",
+		"ARRAY_AGG(x ORDER BY)",
+		"ARRAY_AGG(x ORDER BY y",
+		"ARRAY_AGG(x ORDER BY y, z,)",
+		"ARRAY_AGG(ORDER BY y)",
+		"ARRAY_AGG(x ORDER BY y ORDER BY z)",
+		"ARRAY_AGG(x ORDER BY y, z ORDER BY w)",
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input), Options{OrderedSetAggregates: true})
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+
+	// ORDER only ends the arguments of a call
+	errCases := []ErrorCase{
+		{"a ORDER BY b", "1:3: unexpected ORDER outside of aggregate call arguments"},
+		{"(a ORDER BY b)", "1:4: unexpected ORDER outside of aggregate call arguments"},
+		{"f(x)[a ORDER BY b]", "1:8: unexpected ORDER outside of aggregate call arguments"},
+	}
+	for _, input := range errCases {
+		p := NewWithOptions(lexer.New(input.input), Options{OrderedSetAggregates: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+}
+
+func TestSpecialFloatLiterals(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"Infinity", "Infinity"},
+		{"x = NaN", "(x = NaN)"},
+		{"x > -infinity", "(x > (-infinity))"},
+		{"CAST(NAN AS FLOAT)", "CAST(NAN AS FLOAT)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{SpecialFloatLiterals: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	p := NewWithOptions(lexer.New("Infinity"), Options{SpecialFloatLiterals: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	v, ok := expr.(*ast.NumberLiteral)
+	if !ok {
+		t.Fatalf("expr not *ast.NumberLiteral, got %T", expr)
+	}
+	if !v.IsSpecialFloat() {
+		t.Errorf("v.IsSpecialFloat() not true")
+	}
+
+	testIdentifier(t, parseExpression(t, "Infinity"), "Infinity")
+	testInfixExpression(t, parseExpression(t, "nan = 1"), "nan", token.EQ, 1)
+	if v, ok := parseExpression(t, "1.5").(*ast.NumberLiteral); !ok || v.IsSpecialFloat() {
+		t.Errorf("1.5 should be a number but not a special float")
+	}
+}
+
+func TestModExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"a % b", "(a % b)"},
+		{"a % b % c", "((a % b) % c)"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a * b % c", "(a * (b % c))"},
+		{"a % -b", "(a % (-b))"},
+		{"'%' % x", "('%' % x)"},
+		{"a LIKE '%b%' AND c % 2 = 0", "((a LIKE '%b%') AND ((c % 2) = 0))"},
+		{"a LIKE '%' % x", "(a LIKE ('%' % x))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	infix, ok := parseExpression(t, "x LIKE '100%'").(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("expr not *ast.InfixExpression")
+	}
+	if s, ok := infix.Right.(*ast.StringLiteral); !ok || s.Value != "'100%'" {
+		t.Errorf("infix.Right not the string literal '100%%', got %q", infix.Right.String())
+	}
+
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrTestCase{
+		{"% a", "1:1: `%` is a binary operator and requires an operand before it"},
+		{"a + % b", "1:5: `%` is a binary operator and requires an operand before it"},
+		{"f(%)", "1:3: `%` is a binary operator and requires an operand before it"},
+		{"a %", "1:4: " + EOFErr.Error()},
+		{"a % % b", "1:5: `%` is a binary operator and requires an operand before it"},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+}
+
+func TestSubqueryError(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"x IN (SELECT id FROM t)", "1:7: subqueries are not supported in expression-only mode; got keyword SELECT"},
+		{"x NOT IN (select id from t)", "1:11: subqueries are not supported in expression-only mode; got keyword SELECT"},
+		{"EXISTS (SELECT 1)", "1:9: subqueries are not supported in expression-only mode; got keyword SELECT"},
+		{"NOT EXISTS (SELECT 1)", "1:13: subqueries are not supported in expression-only mode; got keyword SELECT"},
+		{"(SELECT max(x) FROM t) > 1", "1:2: subqueries are not supported in expression-only mode; got keyword SELECT"},
+		{"x IN (WITH t AS (SELECT 1) SELECT * FROM t)", "1:7: subqueries are not supported in expression-only mode; got keyword WITH"},
+		{"x IN (VALUES (1), (2))", "1:7: subqueries are not supported in expression-only mode; got keyword VALUES"},
+		{"EXISTS (x)", "1:8: EXISTS requires a subquery, which is not supported in expression-only mode"},
+	}
+	for _, input := range inputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	testInfixExpression(t, parseExpression(t, "x IN (1)"), "x", token.IN, 1)
+}
+
+func TestDoubleMinus(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"1- -1", "(1 - (-1))"},
+		{"1 - -1", "(1 - (-1))"},
+		{"a - -b", "(a - (-b))"},
+		{"a- -b", "(a - (-b))"},
+		{"- -a", "(-(-a))"},
+		{"a -\n-b", "(a - (-b))"},
+		{"a-\t-b", "(a - (-b))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrTestCase{
+		{"1 -- comment", `1:3: not support SQL comment: "-- comment"`},
+		{"a--b", `1:2: not support SQL comment: "--b"`},
+		{"a-- b", `1:2: not support SQL comment: "-- b"`},
+		{"a -- b", `1:3: not support SQL comment: "-- b"`},
+		{"--a", `1:1: not support SQL comment: "--a"`},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+}
+
+func TestSignChains(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"+-x", "(+(-x))"},
+		{"-+x", "(-(+x))"},
+		{"- -x", "(-(-x))"},
+		{"+-1", "(+(-1))"},
+		{"2 * -+x", "(2 * (-(+x)))"},
+		{"1e+3", "1e+3"},
+		{"1e+3+3", "(1e+3 + 3)"},
+		{"1-+-x", "(1 - (+(-x)))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	errInputs := []string{"1 e + 3", "1e+-3", "1e-+3"}
+	for _, input := range errInputs {
+		if _, err := parseExpressionWithError(t, input); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
+	}
+}
+
+func TestStatementLevelKeywords(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"col TABLESAMPLE BERNOULLI (10)", "1:5: TABLESAMPLE is a statement-level construct not supported in expression mode"},
+		{"lateral f(x)", "1:1: LATERAL is a statement-level construct not supported in expression mode"},
+		{"a = 1 QUALIFY b", "1:7: QUALIFY is a statement-level construct not supported in expression mode"},
+		{"x = 1 pivot", "1:7: PIVOT is a statement-level construct not supported in expression mode"},
+	}
+	for _, input := range inputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	// The words are columns in operand position
+	type ValidCase struct {
+		input string
+		str   string
+	}
+	valid := []ValidCase{
+		{"pivot = 1", "(pivot = 1)"},
+		{"t.lateral", "t.lateral"},
+		{"f(x) + pivot", "(f(x) + pivot)"},
+		{"returning IS NULL", "(returning IS NULL)"},
+	}
+	for _, input := range valid {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+}
+
+func TestOrderByOrdinals(t *testing.T) {
+	input := "f() WITHIN GROUP (ORDER BY 1, 2 DESC, x + 1, 1.5, 0, 0x1)"
+	p := NewWithOptions(lexer.New(input), Options{OrderedSetAggregates: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	if expr.String() != "f() WITHIN GROUP (ORDER BY 1, 2 DESC, (x + 1), 1.5, 0, 0x1)" {
+		t.Errorf("expr.String() wrong, got %q", expr.String())
+	}
+
+	type Ordinal struct {
+		n  int
+		ok bool
+	}
+
+	expected := []Ordinal{{1, true}, {2, true}, {0, false}, {0, false}, {0, false}, {0, false}}
+	call := expr.(*ast.CallExpression)
+	if len(call.WithinGroup) != len(expected) {
+		t.Fatalf("len(call.WithinGroup) not %d, got %d", len(expected), len(call.WithinGroup))
+	}
+	for i, item := range call.WithinGroup {
+		n, ok := item.Ordinal()
+		if n != expected[i].n || ok != expected[i].ok {
+			t.Errorf("call.WithinGroup[%d].Ordinal() not (%d, %t), got (%d, %t)", i, expected[i].n, expected[i].ok, n, ok)
+		}
+	}
+	if call.WithinGroup[1].Direction != token.DESC {
+		t.Errorf("call.WithinGroup[1].Direction not DESC, got %q", call.WithinGroup[1].Direction)
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	type TestCase struct {
+		input     string
+		negated   bool
+		predicate token.Type
+		str       string
+	}
+
+	inputs := []TestCase{
+		{"x IS NULL", false, token.NULL, "(x IS NULL)"},
+		{"x IS NOT NULL", true, token.NULL, "(x IS NOT NULL)"},
+		{"x IS TRUE", false, token.TRUE, "(x IS TRUE)"},
+		{"x IS NOT TRUE", true, token.TRUE, "(x IS NOT TRUE)"},
+		{"x IS FALSE", false, token.FALSE, "(x IS FALSE)"},
+		{"x is not false", true, token.FALSE, "(x IS NOT false)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		is, ok := expr.(*ast.IsExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.IsExpression, got %T", input.input, expr)
+			continue
+		}
+		testIdentifier(t, is.Left, "x")
+		if is.Negated != input.negated {
+			t.Errorf("%q: is.Negated not %t, got %t", input.input, input.negated, is.Negated)
+		}
+		if is.Predicate != input.predicate {
+			t.Errorf("%q: is.Predicate not %q, got %q", input.input, input.predicate, is.Predicate)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	// Any other right side is still an infix expression
+	testInfixExpression(t, parseExpression(t, "x IS y"), "x", token.IS, "y")
+	testInfixExpression(t, parseExpression(t, "x IS NOT y"), "x", token.IS_NOT, "y")
+	if expr := parseExpression(t, "a + 1 IS NULL AND b IS NOT TRUE"); expr.String() != "((a + (1 IS NULL)) AND (b IS NOT TRUE))" {
+		t.Errorf("expr.String() not %q, got %q", "((a + (1 IS NULL)) AND (b IS NOT TRUE))", expr.String())
+	}
+}
+
+func TestIsDocumentExpression(t *testing.T) {
+	type TestCase struct {
+		input   string
+		negated bool
+		str     string
+	}
+
+	inputs := []TestCase{
+		{"x IS DOCUMENT", false, "(x IS DOCUMENT)"},
+		{"x is not document", true, "(x IS NOT DOCUMENT)"},
+		{"x IS NOT DOCUMENT", true, "(x IS NOT DOCUMENT)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{XMLPredicates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		v, ok := expr.(*ast.IsDocumentExpression)
+		if !ok {
+			t.Errorf("expr not *ast.IsDocumentExpression, got %T", expr)
+			continue
+		}
+		testIdentifier(t, v.Expr, "x")
+		if v.Negated != input.negated {
+			t.Errorf("v.Negated not %t, got %t", input.negated, v.Negated)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	others := map[string]string{
+		"x IS DOCUMENT AND document = 1": "((x IS DOCUMENT) AND (document = 1))",
+		"f(document) IS NOT DOCUMENT":    "(f(document) IS NOT DOCUMENT)",
+	}
+	for input, str := range others {
+		p := NewWithOptions(lexer.New(input), Options{XMLPredicates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input, err)
+			continue
+		}
+		if expr.String() != str {
+			t.Errorf("expr.String() not %q, got %q", str, expr.String())
+		}
+	}
+
+	// Without the option `DOCUMENT` is a plain identifier
+	expr := parseExpression(t, "x IS DOCUMENT")
+	if _, ok := expr.(*ast.IsDocumentExpression); ok {
+		t.Errorf("x IS DOCUMENT should not be an *ast.IsDocumentExpression without XMLPredicates")
+	}
+}
+
+func TestIsASetExpression(t *testing.T) {
+	type TestCase struct {
+		input   string
+		negated bool
+		str     string
+	}
+
+	inputs := []TestCase{
+		{"col IS A SET", false, "(col IS A SET)"},
+		{"col is a set", false, "(col IS A SET)"},
+		{"col IS NOT A SET", true, "(col IS NOT A SET)"},
+		{"col is not a Set", true, "(col IS NOT A SET)"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{OracleSetPredicates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		v, ok := expr.(*ast.IsASetExpression)
+		if !ok {
+			t.Errorf("expr not *ast.IsASetExpression, got %T", expr)
+			continue
+		}
+		testIdentifier(t, v.Expr, "col")
+		if v.Negated != input.negated {
+			t.Errorf("v.Negated not %t, got %t", input.negated, v.Negated)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		if !ast.IsPredicate(expr) {
+			t.Errorf("%q should be a predicate", input.input)
+		}
+		if rendered := ast.Render(expr, ast.RenderOptions{Parentheses: ast.ParenthesesMinimal}); !strings.EqualFold(rendered, input.input) {
+			t.Errorf("%q: rendered as %q", input.input, rendered)
+		}
+	}
+
+	others := map[string]string{
+		"t.items IS A SET AND a = 1": "((t.items IS A SET) AND (a = 1))",
+		"f(a) IS NOT A SET":          "(f(a) IS NOT A SET)",
+	}
+	for input, str := range others {
+		p := NewWithOptions(lexer.New(input), Options{OracleSetPredicates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input, err)
+			continue
+		}
+		if expr.String() != str {
+			t.Errorf("expr.String() not %q, got %q", str, expr.String())
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"col IS A", `1:9: expected SET after IS A, got "" instead`},
+		{"col IS NOT A b", `1:14: expected SET after IS NOT A, got "b" instead`},
+		{"col IS SET", ""},
+		{"col = SET", ""},
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input.input), Options{OracleSetPredicates: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+
+	// Disabled by default, `SET` stays denied
+	if _, err := parseExpressionWithError(t, "col IS A SET"); err == nil {
+		t.Errorf("col IS A SET should be parsed with error by default")
+	}
+}
+
+func TestPeriodPredicates(t *testing.T) {
+	type TestCase struct {
+		input    string
+		operator token.Type
+		str      string
+	}
+
+	inputs := []TestCase{
+		{"p1 CONTAINS p2", token.CONTAINS, "(p1 CONTAINS p2)"},
+		{"p1 precedes p2", token.PRECEDES, "(p1 PRECEDES p2)"},
+		{"p1 SUCCEEDS p2", token.SUCCEEDS, "(p1 SUCCEEDS p2)"},
+		{"p1 IMMEDIATELY PRECEDES p2", token.IMMEDIATELY_PRECEDES, "(p1 IMMEDIATELY PRECEDES p2)"},
+		{"p1 immediately succeeds p2", token.IMMEDIATELY_SUCCEEDS, "(p1 IMMEDIATELY SUCCEEDS p2)"},
+		{"(s1, e1) PRECEDES (s2, e2)", token.PRECEDES, "((s1, e1) PRECEDES (s2, e2))"},
+		{"PERIOD(s, e) CONTAINS d + 1", token.CONTAINS, "(PERIOD(s, e) CONTAINS (d + 1))"},
+		{"p1 CONTAINS p2 AND p2 SUCCEEDS p3", token.AND, "((p1 CONTAINS p2) AND (p2 SUCCEEDS p3))"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{PeriodPredicates: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		infix, ok := expr.(*ast.InfixExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.InfixExpression, got %T", input.input, expr)
+			continue
+		}
+		if infix.Operator() != input.operator {
+			t.Errorf("%q: operator not %q, got %q", input.input, input.operator, infix.Operator())
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		if !ast.IsPredicate(expr) {
+			t.Errorf("%q should be a predicate", input.input)
+		}
+		// Keywords are upper-cased when rendering
+		if rendered := ast.Render(expr, ast.RenderOptions{Parentheses: ast.ParenthesesMinimal}); !strings.EqualFold(rendered, input.input) {
+			t.Errorf("%q: rendered as %q", input.input, rendered)
+		}
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"p1 IMMEDIATELY p2", `1:16: expected PRECEDES or SUCCEEDS after IMMEDIATELY, got "p2" instead`},
+		{"p1 IMMEDIATELY CONTAINS p2", `1:16: expected PRECEDES or SUCCEEDS after IMMEDIATELY, got "CONTAINS" instead`},
+		{"p1 PRECEDES", ""},
+		{"p1 other p2", ""},
+	}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input.input), Options{PeriodPredicates: true})
+		_, err := p.ParseExpression()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+
+	// The words are still identifiers in other positions
+	p := NewWithOptions(lexer.New("contains(precedes, 'x')"), Options{PeriodPredicates: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	testCallExpression(t, expr, "contains", []string{"precedes", "'x'"})
+
+	// Disabled by default
+	if _, err := parseExpressionWithError(t, "p1 PRECEDES p2"); err == nil {
+		t.Errorf("p1 PRECEDES p2 should be parsed with error by default")
+	}
+}
+
+func TestIsOfExpression(t *testing.T) {
+	type TestCase struct {
+		input   string
+		types   []string
+		negated bool
+		str     string
+	}
+
+	inputs := []TestCase{
+		{"x IS OF (int)", []string{"int"}, false, "(x IS OF (int))"},
+		{"x is of (int, varchar)", []string{"int", "varchar"}, false, "(x IS OF (int, varchar))"},
+		{"x IS NOT OF (DECIMAL(10, 2))", []string{"DECIMAL(10, 2)"}, true, "(x IS NOT OF (DECIMAL(10, 2)))"},
+		{"x IS NOT OF (int, text, date)", []string{"int", "text", "date"}, true, "(x IS NOT OF (int, text, date))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.IsOfExpression)
+		if !ok {
+			t.Errorf("expr not *ast.IsOfExpression, got %T", expr)
+			continue
+		}
+		testIdentifier(t, v.Expr, "x")
+		if len(v.Types) != len(input.types) {
+			t.Errorf("len(v.Types) not %d, got %d", len(input.types), len(v.Types))
+			continue
+		}
+		for i, typ := range v.Types {
+			if typ.String() != input.types[i] {
+				t.Errorf("v.Types[%d] not %q, got %q", i, input.types[i], typ.String())
+			}
+		}
+		if v.Negated != input.negated {
+			t.Errorf("v.Negated not %t, got %t", input.negated, v.Negated)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	expr := parseExpression(t, "x IS OF (int) AND y")
+	if expr.String() != "((x IS OF (int)) AND y)" {
+		t.Errorf("expr.String() wrong, got %q", expr.String())
+	}
+
+	errInputs := []string{
+		"x IS OF",
+		"x IS OF int",
+		"x IS OF ()",
+		"x IS OF (int,)",
+		"x IS OF (int",
+		"x IS OF (1)",
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+}
+
+func TestContextualKeywords(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	// The words are columns outside of their constructs
+	inputs := []TestCase{
+		{"normalized = 1", "(normalized = 1)"},
+		{"of + 1", "(of + 1)"},
+		{"leading AND trailing OR both", "((leading AND trailing) OR both)"},
+		{"for > 0", "(for > 0)"},
+		{"some = 1", "(some = 1)"},
+		{"collate = 'C'", "(collate = 'C')"},
+		{"f(normalized, of)", "f(normalized, of)"},
+		{"TRIM(leading)", "TRIM(leading)"},
+		{"TRIM(both, 'x')", "TRIM(both, 'x')"},
+		{"TRIM(LEADING leading FROM trailing)", "TRIM(LEADING leading FROM trailing)"},
+		{"SUBSTRING(for FROM 2 for 3)", "SUBSTRING(for FROM 2 FOR 3)"},
+		{"SUBSTRING(s FOR for)", "SUBSTRING(s FOR for)"},
+		{"normalized IS NORMALIZED", "(normalized IS NORMALIZED)"},
+		{"of IS OF (int)", "(of IS OF (int))"},
+		{"collate COLLATE \"C\"", "(collate COLLATE \"C\")"},
+		{"some LIKE some ('x')", "(some LIKE SOME ('x'))"},
+		{"escape = 1", "(escape = 1)"},
+		{"similar + x", "(similar + x)"},
+		{"SUBSTRING(similar SIMILAR escape ESCAPE '#')", "SUBSTRING(similar SIMILAR escape ESCAPE '#')"},
+		{"escape LIKE similar escape '!'", "(escape LIKE similar ESCAPE '!')"},
+		{"placing = 1", "(placing = 1)"},
+		{"OVERLAY(placing placing 'x' FROM 2)", "OVERLAY(placing PLACING 'x' FROM 2)"},
+		{"OVERLAY(s, placing)", "OVERLAY(s, placing)"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	// ALL is still a denied keyword outside of LIKE ALL
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"a for", `1:3: unexpected for outside of SUBSTRING or OVERLAY`},
+		{"a similar b", `1:3: unexpected similar outside of SUBSTRING`},
+		{"a escape b", `1:3: unexpected escape outside of LIKE or SUBSTRING`},
+		{"a placing b", `1:3: unexpected placing outside of OVERLAY`},
+		{"all = 1", `1:1: not support keyword: "all"`},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+			continue
+		}
+		if err.Error() != input.err {
+			t.Errorf("err.Error() not %q, got %q", input.err, err.Error())
+		}
+	}
+
+	// An identifier after an operand is a contextual keyword before an implicit alias
+	p := NewWithOptions(lexer.New(`a collate "C"`), Options{ImplicitAliases: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := expr.(*ast.CollateExpression); !ok {
+		t.Errorf("expr not *ast.CollateExpression, got %T", expr)
+	}
+}
+
+func TestRemainingTokens(t *testing.T) {
+	p := New(lexer.New("a + b , c"))
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	testInfixExpression(t, expr, "a", token.PLUS, "b")
+
+	expected := []token.Token{
+		{Type: token.COMMA, Literal: ",", Start: 6, End: 7, Line: 1, Column: 7},
+		{Type: token.IDENT, Literal: "c", Start: 8, End: 9, Line: 1, Column: 9},
+	}
+	remaining := p.RemainingTokens()
+	if len(remaining) != len(expected) {
+		t.Fatalf("len(remaining) not %d, got %d", len(expected), len(remaining))
+	}
+	for i, tok := range remaining {
+		if tok != expected[i] {
+			t.Errorf("remaining[%d] not %s, got %s", i, expected[i], tok)
+		}
+	}
+
+	if remaining := p.RemainingTokens(); len(remaining) != 0 {
+		t.Errorf("tokens should all be consumed, got %v", remaining)
+	}
+
+	p = New(lexer.New("x = 1"))
+	if _, err := p.ParseExpression(); err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	if remaining := p.RemainingTokens(); len(remaining) != 0 {
+		t.Errorf("remaining should be empty, got %v", remaining)
+	}
+
+	p = New(lexer.New("f(x) ) x NOT IN y"))
+	if _, err := p.ParseExpression(); err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	var types []token.Type
+	for _, tok := range p.RemainingTokens() {
+		types = append(types, tok.Type)
+	}
+	if fmt.Sprint(types) != fmt.Sprint([]token.Type{token.RPAREN, token.IDENT, token.NOT_IN, token.IDENT}) {
+		t.Errorf("remaining types wrong, got %v", types)
+	}
+}
+
+func TestDistinctExpression(t *testing.T) {
+	testPrefixExpression(t, parseExpression(t, "DISTINCT x"), "DISTINCT", "x")
+
+	type TestCase struct {
+		input string
+		args  []string
+		str   string
+	}
+
+	// A leading DISTINCT of call arguments is the aggregate modifier,
+	// it used to be a prefix of the first argument like `COUNT((DISTINCT x), y)`
+	inputs := []TestCase{
+		{"COUNT(DISTINCT x)", []string{"x"}, "COUNT(DISTINCT x)"},
+		{"COUNT(DISTINCT x, y)", []string{"x", "y"}, "COUNT(DISTINCT x, y)"},
+		{"count(distinct a + 1)", []string{"(a + 1)"}, "count(DISTINCT (a + 1))"},
+		{"SUM(DISTINCT (x))", []string{"x"}, "SUM(DISTINCT x)"},
+		// Only the first DISTINCT is the modifier
+		{"f(DISTINCT x, DISTINCT y)", []string{"x", "(DISTINCT y)"}, "f(DISTINCT x, (DISTINCT y))"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		call, ok := expr.(*ast.CallExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CallExpression, got %T", expr)
+			continue
+		}
+		if !call.Distinct {
+			t.Errorf("%q: call.Distinct not true", input.input)
+		}
+		testCallExpression(t, call, call.Fn.String(), input.args)
+	}
+
+	if call := parseExpression(t, "COUNT(x)").(*ast.CallExpression); call.Distinct {
+		t.Errorf("COUNT(x): call.Distinct not false")
+	}
+	if _, err := parseExpressionWithError(t, "COUNT(DISTINCT)"); err == nil || err.Error() != "1:15: DISTINCT requires an argument, like `COUNT(DISTINCT x)`" {
+		t.Errorf("COUNT(DISTINCT): err wrong, got %v", err)
+	}
+
+	const message = "`DISTINCT` can only prefix an expression like `COUNT(DISTINCT x)` and cannot be used between two expressions"
+	errInputs := map[string]string{
+		"x DISTINCT y":    "1:3: " + message,
+		"f(x DISTINCT y)": "1:5: " + message,
+		"x distinct":      "1:3: " + message,
+	}
+	for input, expected := range errInputs {
+		_, err := parseExpressionWithError(t, input)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%q: err not %q, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestNotNullConstraint(t *testing.T) {
+	type ErrTestCase struct {
+		input string
+		err   string
+	}
+
+	errInputs := []ErrTestCase{
+		{"col NOT NULL", "1:5: `col NOT NULL` is a column constraint, use `col IS NOT NULL` to test for NULL"},
+		{"a AND b NOT NULL", "1:9: `b NOT NULL` is a column constraint, use `b IS NOT NULL` to test for NULL"},
+		{"f(x) not null", "1:6: `f(x) NOT NULL` is a column constraint, use `f(x) IS NOT NULL` to test for NULL"},
+		{"a NOT b", "1:3: `NOT` can only prefix an expression like `NOT x` and cannot be used between two expressions"},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"col NOT NULL", "(col IS NOT NULL)"},
+		// The same precedence as `IS NOT NULL`
+		{"a + 1 NOT NULL", "(a + (1 IS NOT NULL))"},
+		{"a = b NOT NULL", "(a = (b IS NOT NULL))"},
+		{"x NOT NULL AND y NOT NULL", "((x IS NOT NULL) AND (y IS NOT NULL))"},
+		{"NOT x NOT NULL", "(NOT (x IS NOT NULL))"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{NotNullAsIsNotNull: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	p := NewWithOptions(lexer.New("col NOT NULL"), Options{NotNullAsIsNotNull: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	is, ok := expr.(*ast.IsExpression)
+	if !ok || !is.Negated || is.Predicate != token.NULL {
+		t.Errorf("col NOT NULL not an IS NOT NULL *ast.IsExpression, got %#v", expr)
+	}
+	if d := ast.Diff(expr, parseExpression(t, "col IS NOT NULL")); d != "" {
+		t.Errorf("col NOT NULL not the same as col IS NOT NULL: %s", d)
+	}
+
+	p = NewWithOptions(lexer.New("a NOT b"), Options{NotNullAsIsNotNull: true})
+	if _, err := p.ParseExpression(); err == nil {
+		t.Errorf("%q should parsed error, but not", "a NOT b")
+	}
+}
+
+func TestMaxNumberMagnitude(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"x = 1000000", ""},
+		{"x = 1e6", ""},
+		{"x = -1e6", ""},
+		{"x = 0.5e-300", ""},
+		{"x = 0xF4240", ""},
+		{"x = 1000001", "1:5: number literal 1000001 exceeds the maximum magnitude 1e+06"},
+		{"x = 1e308", "1:5: number literal 1e308 exceeds the maximum magnitude 1e+06"},
+		{"x = -1e400", "1:6: number literal 1e400 exceeds the maximum magnitude 1e+06"},
+		{"f(1, 2.5e7)", "1:6: number literal 2.5e7 exceeds the maximum magnitude 1e+06"},
+		{"x = 0xFFFFFFFFFFFFFFFFFFFF", "1:5: number literal 0xFFFFFFFFFFFFFFFFFFFF exceeds the maximum magnitude 1e+06"},
+		{"x = 01000001", ""},
+		{"x = 07777777", "1:5: number literal 07777777 exceeds the maximum magnitude 1e+06"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{MaxNumberMagnitude: 1e6})
+		_, err := p.ParseExpression()
+		if input.err == "" && err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+		} else if input.err != "" && (err == nil || err.Error() != input.err) {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	p := NewWithOptions(lexer.New("x > Infinity"), Options{MaxNumberMagnitude: 1e6, SpecialFloatLiterals: true})
+	if _, err := p.ParseExpression(); err == nil {
+		t.Errorf("Infinity should exceed the maximum magnitude")
+	}
+
+	if _, err := parseExpressionWithError(t, "x = 1e308"); err != nil {
+		t.Errorf("unlimited by default, got %s", err)
+	}
+}
+
+func TestSimpleCaseWhenExpression(t *testing.T) {
+	type TestCase struct {
+		input   string
+		operand string
+		str     string
+	}
+
+	inputs := []TestCase{
+		{"CASE x WHEN 1 THEN 'a' WHEN 2 THEN 'b' ELSE 'c' END", "x", "CASE x WHEN 1 THEN 'a' WHEN 2 THEN 'b' ELSE 'c' END"},
+		{"case a + b when 0 then NULL end", "(a + b)", "CASE (a + b) WHEN 0 THEN NULL END"},
+		{"CASE CASE WHEN a THEN b END WHEN c THEN d END", "CASE WHEN a THEN b END", "CASE CASE WHEN a THEN b END WHEN c THEN d END"},
+		{"CASE WHEN a THEN b END", "", "CASE WHEN a THEN b END"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		v, ok := expr.(*ast.CaseWhenExpression)
+		if !ok {
+			t.Errorf("expr not *ast.CaseWhenExpression, got %T", expr)
+			continue
+		}
+		if input.operand == "" && v.Operand != nil {
+			t.Errorf("v.Operand not nil, got %q", v.Operand.String())
+		} else if input.operand != "" && (v.Operand == nil || v.Operand.String() != input.operand) {
+			t.Errorf("v.Operand not %q, got %v", input.operand, v.Operand)
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+}
+
+func TestGroupingConstructs(t *testing.T) {
+	type TestCase struct {
+		input string
+		kind  string
+		args  int
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"ROLLUP(a, b)", ast.GroupingRollup, 2, "ROLLUP(a, b)"},
+		{"rollup((a, b), c)", ast.GroupingRollup, 2, "ROLLUP((a, b), c)"},
+		{"CUBE(a)", ast.GroupingCube, 1, "CUBE(a)"},
+		{"GROUPING SETS((a, b), c, ())", ast.GroupingSets, 3, "GROUPING SETS((a, b), c, ())"},
+		{"grouping sets (a, ROLLUP(b, c))", ast.GroupingSets, 2, "GROUPING SETS(a, ROLLUP(b, c))"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{GroupingConstructs: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) failed: %s", input.input, err)
+		}
+
+		g, ok := expr.(*ast.GroupingConstruct)
+		if !ok {
+			t.Fatalf("%q: expr is not *ast.GroupingConstruct, got %T", input.input, expr)
+		}
+		if g.Kind != input.kind {
+			t.Errorf("%q: Kind not %q, got %q", input.input, input.kind, g.Kind)
+		}
+		if len(g.Args) != input.args {
+			t.Errorf("%q: len(Args) not %d, got %d", input.input, input.args, len(g.Args))
+		}
+		if g.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, g.String())
+		}
+	}
+
+	// GROUPING(col) stays a call and SETS a plain identifier
+	p := NewWithOptions(lexer.New("GROUPING(a) + sets"), Options{GroupingConstructs: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	if expr.String() != "(GROUPING(a) + sets)" {
+		t.Errorf("expr.String() not %q, got %q", "(GROUPING(a) + sets)", expr.String())
+	}
+
+	errInputs := []string{"ROLLUP()", "CUBE(a", "GROUPING SETS a", "GROUPING SETS()"}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input), Options{GroupingConstructs: true})
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should be parsed with error", input)
+		}
+	}
+
+	// Disabled by default
+	expr = parseExpression(t, "ROLLUP(a, b)")
+	if _, ok := expr.(*ast.CallExpression); !ok {
+		t.Errorf("ROLLUP(a, b) is not *ast.CallExpression by default, got %T", expr)
+	}
+	if _, err := parseExpressionWithError(t, "GROUPING SETS(a)"); err == nil {
+		t.Errorf("GROUPING SETS(a) should be parsed with error by default")
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	type TestCase struct {
+		input string
+		err   string
+	}
+
+	inputs := []TestCase{
+		{"a +\n  )", `2:3: no prefix parse function for ")" found`},
+		{"a = 1\r\n  AND b; c", "2:8: not support token `;`"},
+		{"f(x,\n\t'unclosed)", "2:2: unexpected EOF: 'unclosed)"},
+		{"a +", "1:4: " + EOFErr.Error()},
+		{"(a", "1:3: expected `)` or `,`, got EOF"},
+		{"f(a", `1:4: expected next token to be ")", got "EOF" instead`},
+		{"x BETWEEN 1", `1:12: BETWEEN requires AND between the lower and upper bound, got "EOF"`},
+		{"CAST(x AS 1)", `1:11: CAST requires a target type after AS: expected type name, got "NUMBER"`},
+	}
+	for _, input := range inputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil || err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %v", input.input, input.err, err)
+		}
+	}
+
+	_, err := parseExpressionWithError(t, "a +\n  )")
+	var tokenErr *token.Error
+	if errors.As(err, &tokenErr) {
+		t.Errorf("err of the parser should not be a *token.Error, got %v", err)
+	}
+	_, err = parseExpressionWithError(t, "a;")
+	if !errors.As(err, &tokenErr) || tokenErr.Line != 1 || tokenErr.Column != 2 {
+		t.Errorf("err not a *token.Error at 1:2, got %v", err)
+	}
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	type TestCase struct {
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"col = DEFAULT", "(col = DEFAULT)"},
+		{"col = default", "(col = default)"},
+		{"DEFAULT <> col", "(DEFAULT <> col)"},
+		{"(1, DEFAULT, 'a')", "(1, DEFAULT, 'a')"},
+		{"a = 1 AND b = DEFAULT", "((a = 1) AND (b = DEFAULT))"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), Options{AllowDefaultKeyword: true})
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
 			continue
 		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		if rendered := ast.Render(expr, ast.RenderOptions{Parentheses: ast.ParenthesesMinimal}); !strings.EqualFold(rendered, input.input) {
+			t.Errorf("%q: rendered as %q", input.input, rendered)
+		}
+	}
 
-		for i, when := range input.whens {
-			cond := v.Whens[i].Cond.String()
-			if cond != when.condition {
-				t.Errorf("v.Whens[%d].Cond.String() not %q, got %q", i, when.condition, cond)
-			}
-			then := v.Whens[i].Then.String()
-			if then != when.result {
-				t.Errorf("v.Whens[%d].Then.String() not %q, got %q", i, when.result, then)
-			}
+	p := NewWithOptions(lexer.New("col = default"), Options{AllowDefaultKeyword: true, NormalizeKeywordCase: true})
+	expr, err := p.ParseExpression()
+	if err != nil {
+		t.Fatalf("ParseExpression() failed: %s", err)
+	}
+	infix, ok := expr.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("expr not *ast.InfixExpression, got %T", expr)
+	}
+	if v, ok := infix.Right.(*ast.DefaultLiteral); !ok || v.Literal != "DEFAULT" {
+		t.Errorf("infix.Right not DEFAULT *ast.DefaultLiteral, got %T %q", infix.Right, infix.Right.String())
+	}
+
+	// Other denied keywords stay rejected with the option
+	p = NewWithOptions(lexer.New("col = SELECT"), Options{AllowDefaultKeyword: true})
+	if _, err := p.ParseExpression(); err == nil {
+		t.Errorf("col = SELECT should be parsed with error")
+	}
+
+	// Denied by default
+	if _, err := parseExpressionWithError(t, "col = DEFAULT"); err == nil {
+		t.Errorf("col = DEFAULT should be parsed with error by default")
+	}
+}
+
+func TestAliasExpression(t *testing.T) {
+	type TestCase struct {
+		input string
+		expr  string
+		alias string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{"x AS y", "x", "y", "x AS y"},
+		{"COUNT(*) AS c", "COUNT(*)", "c", "COUNT(*) AS c"},
+		{"TRUE as t", "TRUE", "t", "TRUE AS t"},
+		{"a + b AS total", "(a + b)", "total", "(a + b) AS total"},
+		{"a = 1 AND b AS flag", "((a = 1) AND b)", "flag", "((a = 1) AND b) AS flag"},
+		{`x AS "Total"`, "x", `"Total"`, `x AS "Total"`},
+		{"CAST(x AS int) AS y", "CAST(x AS int)", "y", "CAST(x AS int) AS y"},
+	}
+	for _, input := range inputs {
+		expr := parseExpression(t, input.input)
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+		alias, ok := expr.(*ast.AliasExpression)
+		if !ok {
+			t.Errorf("%q: expr not *ast.AliasExpression, got %T", input.input, expr)
+			continue
+		}
+		if alias.Expr.String() != input.expr {
+			t.Errorf("%q: alias.Expr not %q, got %q", input.input, input.expr, alias.Expr.String())
 		}
+		testIdentifier(t, alias.Alias, input.alias)
+	}
 
-		if input.elseResult != "" {
-			elseStr := v.Else.String()
-			if elseStr != input.elseResult {
-				t.Errorf("v.Else.String() not %q, got %q", input.elseResult, elseStr)
+	// Aliases in a projection-like list
+	testCallExpression(t, parseExpression(t, "f(a AS x, b)"), "f", []string{"a AS x", "b"})
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"x AS", `1:5: expected an alias after AS, got "EOF" instead`},
+		{"x AS 1", `1:6: expected an alias after AS, got "NUMBER" instead`},
+		{"x AS y AS z", `1:8: unexpected "AS" after the alias y`},
+		{"x AS y + 1", `1:8: unexpected "+" after the alias y`},
+		{"AS y", ""},
+		// Implicit aliases need the option
+		{"x y", ""},
+	}
+	for _, input := range errInputs {
+		_, err := parseExpressionWithError(t, input.input)
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+}
+
+func TestImplicitAliases(t *testing.T) {
+	type TestCase struct {
+		opts  Options
+		input string
+		str   string
+	}
+
+	inputs := []TestCase{
+		{Options{ImplicitAliases: true}, "x y", "x AS y"},
+		{Options{ImplicitAliases: true}, "COUNT(*) c", "COUNT(*) AS c"},
+		{Options{ImplicitAliases: true}, "a + b total", "(a + b) AS total"},
+		{Options{ImplicitAliases: true}, `x "Total"`, `x AS "Total"`},
+		{Options{ImplicitAliases: true}, "x AS y", "x AS y"},
+		{Options{ImplicitAliases: true}, "f(a x, b y)", "f(a AS x, b AS y)"},
+		{Options{ImplicitAliases: true}, "CAST(d AS DATE FORMAT 'MM/DD') d", "CAST(d AS DATE FORMAT 'MM/DD') AS d"},
+		{Options{ImplicitAliases: true, PeriodPredicates: true}, "p1 PRECEDES p2", "(p1 PRECEDES p2)"},
+		{Options{ImplicitAliases: true, PeriodPredicates: true}, "p1 PRECEDES p2 p", "(p1 PRECEDES p2) AS p"},
+		{Options{ImplicitAliases: true, PeriodPredicates: true}, "p1 overlap", "p1 AS overlap"},
+	}
+	for _, input := range inputs {
+		p := NewWithOptions(lexer.New(input.input), input.opts)
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("ParseExpression(%q) failed: %s", input.input, err)
+			continue
+		}
+		if expr.String() != input.str {
+			t.Errorf("expr.String() not %q, got %q", input.str, expr.String())
+		}
+	}
+
+	errInputs := []string{"x y z", "x y + 1", "x 'y'"}
+	for _, input := range errInputs {
+		p := NewWithOptions(lexer.New(input), Options{ImplicitAliases: true})
+		if _, err := p.ParseExpression(); err == nil {
+			t.Errorf("%q should parsed error, but not", input)
+		}
+	}
+}
+
+func TestParseExpressionList(t *testing.T) {
+	type TestCase struct {
+		input string
+		strs  []string
+	}
+
+	inputs := []TestCase{
+		{"a", []string{"a"}},
+		{"a + 1", []string{"(a + 1)"}},
+		{"a, b AS x, COUNT(*)", []string{"a", "b AS x", "COUNT(*)"}},
+		{"f(g(a, b), c) AS y, t.*, CASE WHEN a THEN 1 END", []string{"f(g(a, b), c) AS y", "t.*", "CASE WHEN a THEN 1 END"}},
+		{"(a, b), [1, 2]", []string{"(a, b)", "[1, 2]"}},
+	}
+	for _, input := range inputs {
+		list, err := New(lexer.New(input.input)).ParseExpressionList()
+		if err != nil {
+			t.Errorf("ParseExpressionList(%q) failed: %s", input.input, err)
+			continue
+		}
+		if len(list) != len(input.strs) {
+			t.Errorf("%q: len(list) not %d, got %d", input.input, len(input.strs), len(list))
+			continue
+		}
+		for i, expr := range list {
+			if expr.String() != input.strs[i] {
+				t.Errorf("list[%d] not %q, got %q", i, input.strs[i], expr.String())
 			}
 		}
 	}
+
+	if list, err := New(lexer.New("")).ParseExpressionList(); err != nil || list != nil {
+		t.Errorf("ParseExpressionList() of an empty input not nil, got %v, %v", list, err)
+	}
+
+	type ErrorCase struct {
+		input string
+		err   string
+	}
+	errInputs := []ErrorCase{
+		{"a, b,", "1:5: unexpected trailing ,"},
+		{"a )", "1:3: unexpected ) after the expression"},
+		{"a, b) c", "1:5: unexpected ) after the expression"},
+		{"a,, b", ""},
+		{", a", ""},
+		{"a b", ""},
+		{"a, WHEN", ""},
+	}
+	for _, input := range errInputs {
+		_, err := New(lexer.New(input.input)).ParseExpressionList()
+		if err == nil {
+			t.Errorf("%q should parsed error, but not", input.input)
+		} else if input.err != "" && err.Error() != input.err {
+			t.Errorf("%q: err not %q, got %q", input.input, input.err, err)
+		}
+	}
+
+	p := NewWithOptions(lexer.New("a, b, c"), Options{MaxListElements: 2})
+	if _, err := p.ParseExpressionList(); err == nil {
+		t.Errorf("ParseExpressionList() should fail over MaxListElements")
+	}
 }
@@ -2,6 +2,7 @@ package token
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +13,9 @@ const (
 
 	EOF = "EOF"
 
+	WHITESPACE = "WHITESPACE" // only emitted when the lexer option `EmitWhitespace` is on
+	COMMENT    = "COMMENT"    // only emitted when the lexer option `AllowComments` is on
+
 	IDENT = "IDENT"
 
 	BACK_QUOTE_IDENT   = "BACK_QUOTE_IDENT"   // `ident` for MySQL, Sqlite, Clickhouse, ORACLE, SparkSQL
@@ -24,6 +28,9 @@ const (
 	STRING = "STRING"
 	NUMBER = "NUMBER"
 
+	HEX_STRING = "HEX_STRING" // x'4F' or X'4F'
+	BIT_STRING = "BIT_STRING" // b'0101' or B'0101'
+
 	NOT_IN      = "NOT IN"
 	NOT_LIKE    = "NOT LIKE"
 	NOT_BETWEEN = "NOT BETWEEN"
@@ -47,6 +54,9 @@ const (
 	QUESTION = "?"
 	COLON    = ":"
 
+	QUESTION_PIPE = "?|" // Postgres jsonb any key exists
+	QUESTION_AMP  = "?&" // Postgres jsonb all keys exist
+
 	COLON2 = "::" // type case: select 1::int
 
 	COMMA = ","
@@ -63,6 +73,7 @@ const (
 	BANG_LT = "!<"
 
 	EQ       = "="
+	EQ_GT    = "=>" // named argument `f(x => 1)`
 	BANG_EQ  = "!="
 	NOT_EQ   = "<>"
 	LT       = "<"
@@ -72,6 +83,11 @@ const (
 	LT_EQ_GT = "<=>"
 	PRT      = "->"
 	PRT2     = "->>"
+	HASH_GT  = "#>"  // Postgres jsonb path extraction
+	HASH_GT2 = "#>>" // Postgres jsonb path extraction as text
+	AT_GT    = "@>"  // Postgres contains, like array or range containment
+	LT_AT    = "<@"  // Postgres is contained by
+	AMP2     = "&&"  // Postgres overlaps
 
 	AND = "AND"
 	OR  = "OR"
@@ -83,23 +99,67 @@ const (
 	ELSE = "ELSE"
 
 	FROM = "FROM"
+	FOR  = "FOR" // a contextual keyword, see LookupContextualKeyword
+
+	// Contextual keywords of OVERLAY, SUBSTRING and LIKE, see LookupContextualKeyword
+	PLACING = "PLACING"
+	SIMILAR = "SIMILAR"
+	ESCAPE  = "ESCAPE"
+
+	// Contextual keywords of TRIM, see LookupContextualKeyword
+	LEADING  = "LEADING"
+	TRAILING = "TRAILING"
+	BOTH     = "BOTH"
 
 	ASC    = "ASC"
 	DESC   = "DESC"
 	ROWNUM = "ROWNUM" // for Oracle
 
+	// Only keywords with the parser option `OrderedSetAggregates`
+	WITHIN = "WITHIN"
+	GROUP  = "GROUP"
+	ORDER  = "ORDER"
+	BY     = "BY"
+
 	TRUE  = "TRUE"
 	FALSE = "FALSE"
 	NULL  = "NULL"
 
+	// A denied keyword, only a value with the parser option `AllowDefaultKeyword`
+	DEFAULT = "DEFAULT"
+
 	IN      = "IN"
 	LIKE    = "LIKE"
 	IS      = "IS"
 	BETWEEN = "BETWEEN"
 
+	// Contextual keywords of `x IS [NOT] NORMALIZED` and `x IS [NOT] OF (type)`, see LookupContextualKeyword
+	NORMALIZED = "NORMALIZED"
+	OF         = "OF"
+	DOCUMENT   = "DOCUMENT" // for XML `x IS [NOT] DOCUMENT`, not a keyword, see parser.Options
+
+	// For the Oracle nested table predicate `x IS [NOT] A SET`, see parser.Options.
+	// `A` is not a keyword and `SET` is a denied keyword.
+	A   = "A"
+	SET = "SET"
+
+	// SQL:2011 period predicates, not keywords, see parser.Options
+	CONTAINS             = "CONTAINS"
+	PRECEDES             = "PRECEDES"
+	SUCCEEDS             = "SUCCEEDS"
+	IMMEDIATELY          = "IMMEDIATELY"
+	IMMEDIATELY_PRECEDES = "IMMEDIATELY PRECEDES"
+	IMMEDIATELY_SUCCEEDS = "IMMEDIATELY SUCCEEDS"
+
 	ANY    = "ANY"
+	ALL    = "ALL"  // a denied keyword, only a quantifier of `LIKE ALL (pattern, ...)`
+	SOME   = "SOME" // a contextual keyword, see LookupContextualKeyword
 	EXISTS = "EXISTS"
 
+	COLLATE = "COLLATE" // a contextual keyword, see LookupContextualKeyword
+
+	OPERATOR = "OPERATOR" // for Postgres OPERATOR(schema.op), not a keyword, see parser.Options
+
 	DISTINCT = "DISTINCT"
 	AS       = "AS"
 	TOP      = "TOP" // for Oracle
@@ -118,20 +178,58 @@ const (
 type Token struct {
 	Type    Type
 	Literal string
+
+	// The range of the token in the input as rune offsets, [Start, End),
+	// merged tokens like `IS NOT` span both keywords and the whitespace between them
+	Start, End int
+
+	// The 1-based line and column of the first rune of the token,
+	// zero for tokens not read from an input.
+	// A `\n`, a `\r\n` or a lone `\r` ends a line.
+	Line, Column int
+
+	// The keyword in its input casing of the illegal token of a not supported keyword,
+	// like `select`, see `DeniedKeyword`
+	Denied string
 }
 
 func (t Token) String() string {
 	return fmt.Sprintf("Token(%s, %s)", t.Type, t.Literal)
 }
 
+// Pos returns the position of the token as `line:col`, or "" for tokens not read from an input
+func (t Token) Pos() string {
+	if t.Line == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d:%d", t.Line, t.Column)
+}
+
+// IsError returns the error of an illegal token as a `*Error`, or nil for other tokens
 func (t Token) IsError() error {
 	if t.Type == ILLEGAL {
-		return fmt.Errorf(t.Literal)
+		return &Error{Message: t.Literal, Line: t.Line, Column: t.Column}
 	}
 
 	return nil
 }
 
+// Error is the error of an illegal token with its position in the input
+type Error struct {
+	Message      string
+	Line, Column int
+}
+
+// Error prefixes the message with `line:col: ` when the position is known
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
 func (t Token) IsEOF() bool {
 	return t.Type == EOF
 }
@@ -189,6 +287,34 @@ var keywords = map[string]Type{
 
 var notSupportKeywords = map[string]Type{}
 
+const notSupportKeywordFormat = "not support keyword: %q"
+
+// DeniedKeyword returns the upper-cased keyword of an illegal token
+// produced for a not supported keyword like `SELECT`
+func DeniedKeyword(tok Token) (string, bool) {
+	if tok.Type != ILLEGAL || tok.Denied == "" {
+		return "", false
+	}
+
+	keyword := strings.ToUpper(tok.Denied)
+	if _, ok := notSupportKeywords[keyword]; !ok {
+		return "", false
+	}
+
+	return keyword, true
+}
+
+// AllowDeniedKeyword returns the illegal token of the denied keyword typ, like `DEFAULT`,
+// as a token of that type carrying the keyword in its input casing
+func AllowDeniedKeyword(tok Token, typ Type) (Token, bool) {
+	if keyword, ok := DeniedKeyword(tok); !ok || keyword != string(typ) {
+		return tok, false
+	}
+
+	tok.Type, tok.Literal, tok.Denied = typ, tok.Denied, ""
+	return tok, true
+}
+
 func registerNotSupportKeyword(keywords ...string) {
 	for _, keyword := range keywords {
 		notSupportKeywords[keyword] = ILLEGAL
@@ -251,6 +377,90 @@ func init() {
 	)
 }
 
+// Keywords of statement-level constructs pasted into an expression,
+// like `t TABLESAMPLE BERNOULLI (10)`. They are lexed as identifiers,
+// so columns like `pivot` still work, the parser rejects them with guidance after an operand.
+var statementKeywords = map[string]bool{
+	"TABLESAMPLE":     true,
+	"LATERAL":         true,
+	"QUALIFY":         true,
+	"PIVOT":           true,
+	"UNPIVOT":         true,
+	"MATCH_RECOGNIZE": true,
+	"RETURNING":       true,
+}
+
+// IsStatementKeyword reports whether the identifier is a keyword of a statement-level construct,
+// like `TABLESAMPLE` or `QUALIFY`
+func IsStatementKeyword(ident string) bool {
+	return statementKeywords[strings.ToUpper(ident)]
+}
+
+var keywordTypes = map[Type]bool{}
+
+func init() {
+	for _, typ := range keywords {
+		keywordTypes[typ] = true
+	}
+	for _, typ := range orderedSetAggregateKeywords {
+		keywordTypes[typ] = true
+	}
+	for _, typ := range contextualKeywords {
+		keywordTypes[typ] = true
+	}
+	keywordTypes[DEFAULT] = true
+	keywordTypes[ALL] = true
+}
+
+// IsKeyword reports whether t is the type of a keyword token
+func (t Type) IsKeyword() bool {
+	return keywordTypes[t]
+}
+
+// Token categories for syntax highlighting
+const (
+	CategoryKeyword     = "keyword"
+	CategoryIdentifier  = "identifier"
+	CategoryString      = "string"
+	CategoryNumber      = "number"
+	CategoryOperator    = "operator"
+	CategoryPunctuation = "punctuation"
+	CategoryWhitespace  = "whitespace"
+	CategoryComment     = "comment"
+	CategoryError       = "error"
+	CategoryEOF         = "eof"
+)
+
+// Category returns the syntax highlighting category of the token type
+func (t Type) Category() string {
+	switch t {
+	case ILLEGAL:
+		return CategoryError
+	case EOF:
+		return CategoryEOF
+	case WHITESPACE:
+		return CategoryWhitespace
+	case COMMENT:
+		return CategoryComment
+	case IDENT, BACK_QUOTE_IDENT, DOUBLE_QUOTE_IDENT:
+		return CategoryIdentifier
+	case STRING, HEX_STRING, BIT_STRING:
+		return CategoryString
+	case NUMBER:
+		return CategoryNumber
+	case NOT_IN, NOT_LIKE, NOT_BETWEEN, IS_NOT:
+		return CategoryKeyword
+	case COMMA, PERIOD, COLON, COLON2, LPAREN, RPAREN, LBRACKET, RBRACKET:
+		return CategoryPunctuation
+	}
+
+	if t.IsKeyword() {
+		return CategoryKeyword
+	}
+
+	return CategoryOperator
+}
+
 func (t Type) IsTimeUnit() bool {
 	switch t {
 	case DAY, HOUR, MONTH, MINUTE, WEEK, YEAR, QUARTER, SECOND:
@@ -260,12 +470,83 @@ func (t Type) IsTimeUnit() bool {
 	}
 }
 
+var orderedSetAggregateKeywords = map[string]Type{
+	"WITHIN": WITHIN,
+	"GROUP":  GROUP,
+	"ORDER":  ORDER,
+	"BY":     BY,
+	"ASC":    ASC,
+	"DESC":   DESC,
+}
+
+// LookupOrderedSetAggregateKeyword looks up the keywords of
+// `WITHIN GROUP (ORDER BY x [ASC | DESC])`, which are denied or plain identifiers by default
+func LookupOrderedSetAggregateKeyword(ident string) (Token, bool) {
+	typ, ok := orderedSetAggregateKeywords[strings.ToUpper(ident)]
+	return Token{Type: typ, Literal: ident}, ok
+}
+
+// Words lexed as identifiers which the parser only treats as keywords where its grammar
+// expects them, so columns like `normalized` or `for` can still be used elsewhere
+var contextualKeywords = map[string]Type{
+	"FOR":        FOR,
+	"LEADING":    LEADING,
+	"TRAILING":   TRAILING,
+	"BOTH":       BOTH,
+	"NORMALIZED": NORMALIZED,
+	"OF":         OF,
+	"SOME":       SOME,
+	"COLLATE":    COLLATE,
+	"PLACING":    PLACING,
+	"SIMILAR":    SIMILAR,
+	"ESCAPE":     ESCAPE,
+}
+
+// LookupContextualKeyword looks up the keyword spelled by an identifier
+// in the position of a contextual keyword, like `for` of `SUBSTRING(s for 2)`
+func LookupContextualKeyword(ident string) (Type, bool) {
+	typ, ok := contextualKeywords[strings.ToUpper(ident)]
+	return typ, ok
+}
+
+// Keywords returns the sorted upper-cased words `LookupIdent` lexes as keyword tokens,
+// like `CASE` or `BETWEEN`. Reserved words are excluded, see `ReservedWords`.
+func Keywords() []string {
+	var words []string
+	for word := range keywords {
+		if !isReserved(word) {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+
+	return words
+}
+
+// ReservedWords returns the sorted upper-cased words `LookupIdent` rejects as illegal tokens,
+// like `SELECT`
+func ReservedWords() []string {
+	var words []string
+	for word := range notSupportKeywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	return words
+}
+
+func isReserved(word string) bool {
+	_, ok := notSupportKeywords[word]
+	return ok
+}
+
 func LookupIdent(ident string) Token {
 	v := strings.ToUpper(ident)
 	if typ, ok := notSupportKeywords[v]; ok {
 		return Token{
 			Type:    typ,
-			Literal: fmt.Sprintf("not support keyword: %q", ident),
+			Literal: fmt.Sprintf(notSupportKeywordFormat, ident),
+			Denied:  ident,
 		}
 	}
 
@@ -1,6 +1,10 @@
 package token
 
-import "testing"
+import (
+	"sort"
+	"strings"
+	"testing"
+)
 
 func TestLookupIdent(t *testing.T) {
 	type TestCase struct {
@@ -13,6 +17,12 @@ func TestLookupIdent(t *testing.T) {
 		{"When", WHEN},
 		{"True", TRUE},
 		{"FALSE", FALSE},
+		{"tablesample", IDENT},
+		{"Lateral", IDENT},
+		{"tablesamples", IDENT},
+		{"for", IDENT},
+		{"Normalized", IDENT},
+		{"all", ILLEGAL},
 	}
 
 	for _, test := range tests {
@@ -22,3 +32,201 @@ func TestLookupIdent(t *testing.T) {
 		}
 	}
 }
+
+func TestIsKeyword(t *testing.T) {
+	type TestCase struct {
+		input    Type
+		expected bool
+	}
+	tests := []TestCase{
+		{AND, true},
+		{CASE, true},
+		{INTERVAL, true},
+		{COLLATE, true},
+		{ALL, true},
+		{IDENT, false},
+		{PLUS, false},
+		{NOT_IN, false},
+	}
+
+	for _, test := range tests {
+		if actual := test.input.IsKeyword(); actual != test.expected {
+			t.Errorf("%q.IsKeyword() wrong. expected=%t, got=%t", test.input, test.expected, actual)
+		}
+	}
+}
+
+func TestLookupContextualKeyword(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected Type
+		ok       bool
+	}
+	tests := []TestCase{
+		{"for", FOR, true},
+		{"Leading", LEADING, true},
+		{"NORMALIZED", NORMALIZED, true},
+		{"collate", COLLATE, true},
+		{"Escape", ESCAPE, true},
+		{"placing", PLACING, true},
+		{"case", "", false},
+		{"fors", "", false},
+	}
+
+	for _, test := range tests {
+		actual, ok := LookupContextualKeyword(test.input)
+		if actual != test.expected || ok != test.ok {
+			t.Errorf("LookupContextualKeyword(%q) wrong. expected=%q %t, got=%q %t", test.input, test.expected, test.ok, actual, ok)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	type TestCase struct {
+		input    Type
+		expected string
+	}
+	tests := []TestCase{
+		{AND, CategoryKeyword},
+		{NOT_IN, CategoryKeyword},
+		{GROUP, CategoryKeyword},
+		{IDENT, CategoryIdentifier},
+		{BACK_QUOTE_IDENT, CategoryIdentifier},
+		{HEX_STRING, CategoryString},
+		{NUMBER, CategoryNumber},
+		{PLUS, CategoryOperator},
+		{LT_EQ_GT, CategoryOperator},
+		{LPAREN, CategoryPunctuation},
+		{COMMA, CategoryPunctuation},
+		{WHITESPACE, CategoryWhitespace},
+		{ILLEGAL, CategoryError},
+		{EOF, CategoryEOF},
+	}
+
+	for _, test := range tests {
+		if actual := test.input.Category(); actual != test.expected {
+			t.Errorf("%q.Category() wrong. expected=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+}
+
+func TestDeniedKeyword(t *testing.T) {
+	type TestCase struct {
+		input    string
+		expected string
+		ok       bool
+	}
+	tests := []TestCase{
+		{"select", "SELECT", true},
+		{"Order", "ORDER", true},
+		{"selected", "", false},
+		{"and", "", false},
+	}
+
+	for _, test := range tests {
+		actual, ok := DeniedKeyword(LookupIdent(test.input))
+		if actual != test.expected || ok != test.ok {
+			t.Errorf("DeniedKeyword(%q) wrong. expected=(%q, %t), got=(%q, %t)", test.input, test.expected, test.ok, actual, ok)
+		}
+	}
+
+	if _, ok := DeniedKeyword(NewIllegalToken(`not support keyword: "ORDER"`)); ok {
+		t.Errorf("DeniedKeyword() should only accept tokens of not supported keywords")
+	}
+	if _, ok := DeniedKeyword(Token{Type: ILLEGAL, Literal: "denied", Denied: "foo"}); ok {
+		t.Errorf("DeniedKeyword() should only accept not supported keywords")
+	}
+
+	// The keyword doesn't depend on the wording of the message
+	tok := LookupIdent("Order")
+	tok.Literal = "ORDER is not allowed here"
+	if actual, ok := DeniedKeyword(tok); actual != "ORDER" || !ok {
+		t.Errorf("DeniedKeyword() wrong. expected=(%q, %t), got=(%q, %t)", "ORDER", true, actual, ok)
+	}
+	allowed, ok := AllowDeniedKeyword(tok, ORDER)
+	if !ok || allowed.Type != ORDER || allowed.Literal != "Order" || allowed.Denied != "" {
+		t.Errorf("AllowDeniedKeyword() wrong, got %#v", allowed)
+	}
+	if _, ok := AllowDeniedKeyword(tok, GROUP); ok {
+		t.Errorf("AllowDeniedKeyword() should only allow the keyword of the token")
+	}
+}
+
+func TestIsError(t *testing.T) {
+	type TestCase struct {
+		tok      Token
+		pos      string
+		expected string
+	}
+	tests := []TestCase{
+		{Token{Type: ILLEGAL, Literal: "bad", Line: 3, Column: 7}, "3:7", "3:7: bad"},
+		{NewIllegalToken("bad"), "", "bad"},
+	}
+	for _, tt := range tests {
+		if tt.tok.Pos() != tt.pos {
+			t.Errorf("Pos() not %q, got %q", tt.pos, tt.tok.Pos())
+		}
+		err := tt.tok.IsError()
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("IsError() not %q, got %v", tt.expected, err)
+		}
+	}
+
+	if err := (Token{Type: IDENT, Literal: "a", Line: 1, Column: 1}).IsError(); err != nil {
+		t.Errorf("IsError() should be nil, got %v", err)
+	}
+}
+
+func TestKeywordsAndReservedWords(t *testing.T) {
+	keywords := Keywords()
+	reserved := ReservedWords()
+
+	for name, words := range map[string][]string{"Keywords()": keywords, "ReservedWords()": reserved} {
+		if !sort.StringsAreSorted(words) {
+			t.Errorf("%s not sorted: %v", name, words)
+		}
+		for i := 1; i < len(words); i++ {
+			if words[i] == words[i-1] {
+				t.Errorf("%s has duplicate %q", name, words[i])
+			}
+		}
+	}
+
+	contains := func(words []string, word string) bool {
+		i := sort.SearchStrings(words, word)
+		return i < len(words) && words[i] == word
+	}
+	for _, word := range []string{"CASE", "BETWEEN", "NULL", "LIKE", "INTERVAL"} {
+		if !contains(keywords, word) {
+			t.Errorf("Keywords() should contain %q", word)
+		}
+		if contains(reserved, word) {
+			t.Errorf("ReservedWords() should not contain %q", word)
+		}
+	}
+	for _, word := range []string{"qualify", "Pivot", "TABLESAMPLE"} {
+		if !IsStatementKeyword(word) || contains(reserved, strings.ToUpper(word)) {
+			t.Errorf("%q should be a statement keyword lexed as an identifier", word)
+		}
+	}
+	for _, word := range []string{"SELECT", "UNION", "DESC"} {
+		if !contains(reserved, word) {
+			t.Errorf("ReservedWords() should contain %q", word)
+		}
+		if contains(keywords, word) {
+			t.Errorf("Keywords() should not contain %q", word)
+		}
+	}
+
+	// Every listed word is lexed as listed
+	for _, word := range keywords {
+		if tok := LookupIdent(word); tok.Type == IDENT || tok.Type == ILLEGAL {
+			t.Errorf("LookupIdent(%q) not a keyword, got %s", word, tok)
+		}
+	}
+	for _, word := range reserved {
+		if tok := LookupIdent(word); tok.Type != ILLEGAL {
+			t.Errorf("LookupIdent(%q) not illegal, got %s", word, tok)
+		}
+	}
+}